@@ -14,6 +14,9 @@ import (
 	"golang.org/x/time/rate"
 )
 
+// FollowingPageSize is how many follows GetFollowing requests per page.
+const FollowingPageSize = 100
+
 type Client struct {
 	HTTPClient *http.Client
 	BaseURL    string
@@ -159,8 +162,24 @@ func (c *Client) GetMyAccountInfo() (*AccountInfo, error) {
 	return &result.Response.Account, nil
 }
 
+// GetFollowing returns every account the given account follows, fetching the
+// list one page at a time.
 func (c *Client) GetFollowing(accountID string) ([]FollowingAccount, error) {
-	url := fmt.Sprintf("%s/api/v1/account/%s/following?before=0&after=0&limit=999&offset=0", c.BaseURL, accountID)
+	var following []FollowingAccount
+	for offset := 0; ; offset += FollowingPageSize {
+		page, err := c.getFollowingPage(accountID, offset)
+		if err != nil {
+			return nil, err
+		}
+		following = append(following, page...)
+		if len(page) < FollowingPageSize {
+			return following, nil
+		}
+	}
+}
+
+func (c *Client) getFollowingPage(accountID string, offset int) ([]FollowingAccount, error) {
+	url := fmt.Sprintf("%s/api/v1/account/%s/following?before=0&after=0&limit=%d&offset=%d", c.BaseURL, accountID, FollowingPageSize, offset)
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, err
@@ -181,8 +200,7 @@ func (c *Client) GetFollowing(accountID string) ([]FollowingAccount, error) {
 	}
 
 	if !result.Success {
-		//fmt.Printf("%v", url)
-		return nil, fmt.Errorf("failed to get following list")
+		return nil, fmt.Errorf("failed to get following list at offset %d", offset)
 	}
 
 	return result.Response, nil
@@ -214,6 +232,32 @@ func (c *Client) FollowAccount(modelID string) error {
 	return nil
 }
 
+// UnfollowAccount stops following a creator with the bot's account.
+func (c *Client) UnfollowAccount(modelID string) error {
+	url := fmt.Sprintf("%s/api/v1/account/%s/followers?ngsw-bypass=true", c.BaseURL, modelID)
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result FanslyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+
+	if !result.Success {
+		return fmt.Errorf("failed to unfollow account (code %d): %s", result.Error.Code, result.Error.Details)
+	}
+
+	return nil
+}
+
 func (c *Client) getFanslyClientCheck(reqURL string) string {
 	parsedURL, _ := url.Parse(reqURL)
 	urlPath := parsedURL.Path
@@ -4,8 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 )
 
+// MaxAccountsPerLookup is how many usernames GetAccountsInfo sends in one request.
+const MaxAccountsPerLookup = 25
+
 //type AccountInfo struct {
 //	ID       string `json:"id"`
 //	Username string `json:"username"`
@@ -46,3 +51,75 @@ func (c *Client) GetAccountInfo(username string) (*ModelAccountInfo, error) {
 
 	return &result.Response[0], nil
 }
+
+// GetAccountInfoByID looks an account up by its stable ID, which keeps
+// working after the creator changes their username.
+func (c *Client) GetAccountInfoByID(id string) (*ModelAccountInfo, error) {
+	reqURL := fmt.Sprintf("%s/api/v1/account?ids=%s&ngsw-bypass=true", c.BaseURL, url.QueryEscape(id))
+	req, err := http.NewRequest("GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.sendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Success  bool               `json:"success"`
+		Response []ModelAccountInfo `json:"response"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	if !result.Success || len(result.Response) == 0 {
+		return nil, fmt.Errorf("failed to get account info for ID %s", id)
+	}
+
+	return &result.Response[0], nil
+}
+
+// GetAccountsInfo looks several usernames up in as few requests as possible.
+// Usernames that don't exist are simply missing from the result.
+func (c *Client) GetAccountsInfo(usernames []string) ([]ModelAccountInfo, error) {
+	var accounts []ModelAccountInfo
+	for start := 0; start < len(usernames); start += MaxAccountsPerLookup {
+		end := min(start+MaxAccountsPerLookup, len(usernames))
+
+		escaped := make([]string, 0, end-start)
+		for _, username := range usernames[start:end] {
+			escaped = append(escaped, url.QueryEscape(username))
+		}
+
+		reqURL := fmt.Sprintf("%s/api/v1/account?usernames=%s&ngsw-bypass=true", c.BaseURL, strings.Join(escaped, ","))
+		req, err := http.NewRequest("GET", reqURL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.sendRequest(req)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Success  bool               `json:"success"`
+			Response []ModelAccountInfo `json:"response"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if !result.Success {
+			return nil, fmt.Errorf("failed to get account info for %d usernames", end-start)
+		}
+		accounts = append(accounts, result.Response...)
+	}
+
+	return accounts, nil
+}
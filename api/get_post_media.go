@@ -61,7 +61,7 @@ type PostResponse struct {
 }
 
 func (c *Client) GetPostMedia(postID, authToken, userAgent string) ([]AccountMedia, error) {
-	url := fmt.Sprintf("https://apiv3.fansly.com/api/v1/post?ids=%s&ngsw-bypass=true", postID)
+	url := fmt.Sprintf("%s/api/v1/post?ids=%s&ngsw-bypass=true", c.BaseURL, postID)
 
 	client := &http.Client{}
 	req, err := http.NewRequest("GET", url, nil)
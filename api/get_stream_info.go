@@ -22,7 +22,7 @@ type StreamResponse struct {
 }
 
 func (c *Client) GetStreamInfo(modelID string) (*StreamResponse, error) {
-	url := fmt.Sprintf("https://apiv3.fansly.com/api/v1/streaming/channel/%s", modelID)
+	url := fmt.Sprintf("%s/api/v1/streaming/channel/%s", c.BaseURL, modelID)
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %v", err)
@@ -2,11 +2,16 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	//"time"
 )
 
+// ErrNoTimelineAccess is returned when the account used by the bot may not
+// read a creator's timeline, usually because it does not follow or subscribe.
+var ErrNoTimelineAccess = errors.New("no timeline access")
+
 type Post struct {
 	ID        string `json:"id"`
 	Content   string `json:"content"`
@@ -52,7 +57,7 @@ func hasTimelineAccess(response TimelineResponse) bool {
 
 func (c *Client) GetTimelinePost(modelID string) ([]Post, error) {
 	before := "0"
-	url := fmt.Sprintf("https://apiv3.fansly.com/api/v1/timelinenew/%s?before=%s&after=0&wallId&contentSearch&ngsw-bypass=true", modelID, before)
+	url := fmt.Sprintf("%s/api/v1/timelinenew/%s?before=%s&after=0&wallId&contentSearch&ngsw-bypass=true", c.BaseURL, modelID, before)
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %v", err)
@@ -73,7 +78,7 @@ func (c *Client) GetTimelinePost(modelID string) ([]Post, error) {
 	//fmt.Printf("[INFO] [ %s Response ]: %v", modelID, timelineResp)
 
 	if !hasTimelineAccess(timelineResp) {
-		return nil, fmt.Errorf("no timeline access for user %s", modelID)
+		return nil, fmt.Errorf("%w for user %s", ErrNoTimelineAccess, modelID)
 	}
 
 	/*
@@ -11,7 +11,7 @@ import (
 )
 
 func (c *Client) getDeviceID() (string, error) {
-	req, err := http.NewRequest("GET", "https://apiv3.fansly.com/api/v1/device/id", nil)
+	req, err := http.NewRequest("GET", c.BaseURL+"/api/v1/device/id", nil)
 	if err != nil {
 		return "", err
 	}
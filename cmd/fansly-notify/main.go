@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	_ "time/tzdata" // Guild timezones must resolve even on hosts without zoneinfo.
 
 	"github.com/fvckgrimm/discord-fansly-notify/internal/bot"
 	"github.com/fvckgrimm/discord-fansly-notify/internal/config"
@@ -0,0 +1,162 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+// Access levels that cannot be granted with /permissions. Other commands
+// declare one of the grantable scopes in models.
+const (
+	accessMember = "member" // any member of the guild, or the user in a DM
+	accessAdmin  = "admin"  // Administrator, Manage Server or the guild owner
+	accessOwner  = "owner"  // the bot owner
+)
+
+// authorize reports whether the invoking member has the required access. The
+// bot owner may run everything and guild admins everything but owner commands.
+// Other members need a grant, for themselves or one of their roles, with at
+// least the required scope. An empty requirement is treated as accessAdmin.
+func (b *Bot) authorize(s *discordgo.Session, i *discordgo.InteractionCreate, required string) bool {
+	if required == "" {
+		required = accessAdmin
+	}
+
+	if b.isBotOwner(i) {
+		return true
+	}
+
+	switch required {
+	case accessOwner:
+		return false
+	case accessMember:
+		return true
+	}
+
+	if b.hasAdminOrModPermissions(s, i) {
+		return true
+	}
+	if required == accessAdmin {
+		return false
+	}
+
+	grants, err := b.Repo.GetPermissionGrants(i.GuildID)
+	if err != nil {
+		log.Printf("Error fetching permission grants for guild %s: %v", i.GuildID, err)
+		return false
+	}
+
+	return grantsAllow(grants, i.Member, required)
+}
+
+// grantsAllow reports whether any grant to the member or one of their roles
+// covers the required scope. Every member holds the @everyone role, whose ID
+// is the guild's, although Discord doesn't list it among their roles.
+func grantsAllow(grants []models.PermissionGrant, member *discordgo.Member, required string) bool {
+	if member == nil || member.User == nil {
+		return false
+	}
+
+	roles := make(map[string]bool, len(member.Roles))
+	for _, roleID := range member.Roles {
+		roles[roleID] = true
+	}
+
+	for _, grant := range grants {
+		applies := (grant.TargetType == models.GrantTargetUser && grant.TargetID == member.User.ID) ||
+			(grant.TargetType == models.GrantTargetRole && (roles[grant.TargetID] || grant.TargetID == grant.GuildID))
+		if applies && models.ScopeLevel(grant.Scope) >= models.ScopeLevel(required) {
+			return true
+		}
+	}
+	return false
+}
+
+// grantTarget resolves a mentionable option to a role or user target.
+func grantTarget(i *discordgo.InteractionCreate, option *discordgo.ApplicationCommandInteractionDataOption) (string, string, error) {
+	targetID := fmt.Sprint(option.Value)
+	if resolved := i.ApplicationCommandData().Resolved; resolved != nil {
+		if _, ok := resolved.Roles[targetID]; ok {
+			return models.GrantTargetRole, targetID, nil
+		}
+		if _, ok := resolved.Users[targetID]; ok {
+			return models.GrantTargetUser, targetID, nil
+		}
+	}
+	return "", "", fmt.Errorf("couldn't tell whether %s is a role or a member", targetID)
+}
+
+func formatGrantTarget(targetType, targetID string) string {
+	if targetType == models.GrantTargetRole {
+		return fmt.Sprintf("<@&%s>", targetID)
+	}
+	return fmt.Sprintf("<@%s>", targetID)
+}
+
+func (b *Bot) handlePermissionsGrant(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	targetType, targetID, err := grantTarget(i, opts["target"])
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error: %v.", err))
+		return
+	}
+	scope := opts.String("scope")
+
+	if models.ScopeLevel(scope) == 0 {
+		b.editInteractionResponse(s, i, "Invalid scope.")
+		return
+	}
+
+	grant := &models.PermissionGrant{
+		GuildID:    i.GuildID,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Scope:      scope,
+		GrantedBy:  interactionUserID(i),
+		CreatedAt:  b.Clock.Now().Unix(),
+	}
+	if err := b.Repo.SavePermissionGrant(grant); err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error saving grant: %v", err))
+		return
+	}
+
+	b.editInteractionResponse(s, i, fmt.Sprintf("✅ %s now has the **%s** scope.", formatGrantTarget(targetType, targetID), scope))
+}
+
+func (b *Bot) handlePermissionsRevoke(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	targetType, targetID, err := grantTarget(i, opts["target"])
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error: %v.", err))
+		return
+	}
+
+	if err := b.Repo.DeletePermissionGrant(i.GuildID, targetID); err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error revoking grant: %v", err))
+		return
+	}
+
+	b.editInteractionResponse(s, i, fmt.Sprintf("Revoked bot management rights from %s.", formatGrantTarget(targetType, targetID)))
+}
+
+func (b *Bot) handlePermissionsList(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	grants, err := b.Repo.GetPermissionGrants(i.GuildID)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching grants: %v", err))
+		return
+	}
+
+	if len(grants) == 0 {
+		b.editInteractionResponse(s, i, "No grants. Only administrators and members with Manage Server can manage the bot.")
+		return
+	}
+
+	lines := make([]string, 0, len(grants))
+	for _, grant := range grants {
+		lines = append(lines, fmt.Sprintf("• %s: **%s**", formatGrantTarget(grant.TargetType, grant.TargetID), grant.Scope))
+	}
+	b.editInteractionResponse(s, i, "Bot management grants:\n"+strings.Join(lines, "\n")+
+		"\n\nAdministrators and members with Manage Server always have full access.")
+}
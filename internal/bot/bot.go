@@ -1,22 +1,43 @@
 package bot
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/fvckgrimm/discord-fansly-notify/api"
 	"github.com/fvckgrimm/discord-fansly-notify/internal/config"
 	"github.com/fvckgrimm/discord-fansly-notify/internal/database"
-	"github.com/fvckgrimm/discord-fansly-notify/internal/embed"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/events"
 	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
 )
 
 type Bot struct {
 	Session   *discordgo.Session
+	Discord   DiscordAPI
 	APIClient *api.Client
-	Repo      *database.Repository
+	Repo      database.Store
+	Clock     Clock
+	Events    *events.Bus
+	metrics   *eventMetrics
+
+	crossposts *crossposter
+	commands   *commandRegistry
+	health     *healthTracker
+	imports    *pendingImports
+}
+
+// Dependencies are the services a Bot talks to. Discord defaults to Session
+// and Clock to the system clock.
+type Dependencies struct {
+	Session   *discordgo.Session
+	Discord   DiscordAPI
+	APIClient *api.Client
+	Store     database.Store
+	Clock     Clock
 }
 
 func New() (*Bot, error) {
@@ -27,15 +48,41 @@ func New() (*Bot, error) {
 
 	apiClient, _ := api.NewClient(config.FanslyToken, config.UserAgent)
 
-	bot := &Bot{
+	return NewWithDependencies(Dependencies{
 		Session:   discord,
 		APIClient: apiClient,
-		Repo:      database.NewRepository(),
+		Store:     database.NewRepository(),
+	}), nil
+}
+
+// NewWithDependencies creates a bot on top of the given services, so tests can
+// replace Discord, Fansly and the database with fakes.
+func NewWithDependencies(deps Dependencies) *Bot {
+	if deps.Discord == nil {
+		deps.Discord = deps.Session
 	}
+	if deps.Clock == nil {
+		deps.Clock = systemClock{}
+	}
+
+	bot := &Bot{
+		Session:   deps.Session,
+		Discord:   deps.Discord,
+		APIClient: deps.APIClient,
+		Repo:      deps.Store,
+		Clock:     deps.Clock,
+		Events:    events.NewBus(),
+		metrics:   newEventMetrics(),
+	}
+	bot.crossposts = newCrossposter(bot.Discord, bot.Clock)
+	bot.health = newHealthTracker(bot.Clock)
+	bot.imports = newPendingImports()
+	bot.commands = bot.newCommands()
 
 	bot.registerHandlers()
+	bot.registerSubscribers()
 
-	return bot, nil
+	return bot
 }
 
 func (b *Bot) Start() error {
@@ -44,8 +91,13 @@ func (b *Bot) Start() error {
 		return err
 	}
 
+	b.loadCreatorHealth()
+
 	go b.monitorUsers()
 	go b.updateStatusPeriodically()
+	go b.runScheduler()
+	go b.reconcileFollowsPeriodically()
+	go b.crossposts.run()
 
 	return nil
 }
@@ -63,6 +115,7 @@ func (b *Bot) registerHandlers() {
 
 func (b *Bot) guildCreate(s *discordgo.Session, event *discordgo.GuildCreate) {
 	log.Printf("Bot joined a new server: %s", event.Guild.Name)
+	b.restoreGuild(event.Guild.ID, event.Guild.Name)
 	b.updateBotStatus()
 }
 
@@ -70,13 +123,8 @@ func (b *Bot) guildDelete(s *discordgo.Session, event *discordgo.GuildDelete) {
 	// This event fires when the bot is kicked, banned, or the guild is deleted.
 	// If event.Unavailable is true, it means a Discord outage, so we shouldn't delete data.
 	if !event.Unavailable {
-		log.Printf("Bot removed from guild: %s. Cleaning up associated data.", event.ID)
-		err := b.Repo.DeleteAllUsersInGuild(event.ID)
-		if err != nil {
-			log.Printf("Error deleting users for guild %s: %v", event.ID, err)
-		} else {
-			log.Printf("Successfully cleaned up data for guild %s", event.ID)
-		}
+		log.Printf("Bot removed from guild: %s.", event.ID)
+		b.orphanGuild(event.ID)
 	} else {
 		log.Printf("Guild %s became unavailable.", event.ID)
 	}
@@ -92,7 +140,7 @@ func (b *Bot) monitorUsers() {
 	if numWorkers <= 0 {
 		numWorkers = 1 // Ensure at least one worker.
 	}
-	jobs := make(chan []models.MonitoredUser, 100) // Buffered channel
+	jobs := make(chan monitoringJob, 100) // Buffered channel
 
 	// Start long-lived workers that will process jobs as they come in.
 	for w := 1; w <= numWorkers; w++ {
@@ -108,7 +156,34 @@ func (b *Bot) monitorUsers() {
 	}
 }
 
-func (b *Bot) dispatchMonitoringJobs(jobs chan<- []models.MonitoredUser) {
+// RunMonitoringCycle checks every monitored creator once and returns after the
+// resulting notifications have been handed to Discord.
+func (b *Bot) RunMonitoringCycle() {
+	jobs := make(chan monitoringJob)
+
+	var wg sync.WaitGroup
+	for w := 1; w <= max(config.MonitorWorkerCount, 1); w++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			b.worker(id, jobs)
+		}(w)
+	}
+
+	b.dispatchMonitoringJobs(jobs)
+	close(jobs)
+	wg.Wait()
+}
+
+// monitoringJob is the subscriptions of one creator, checked by a worker.
+// The jobs of a cycle share follows, so the bot's following list is fetched
+// at most once per cycle.
+type monitoringJob struct {
+	userEntries []models.MonitoredUser
+	follows     *followCache
+}
+
+func (b *Bot) dispatchMonitoringJobs(jobs chan<- monitoringJob) {
 	users, err := b.Repo.GetMonitoredUsers()
 	if err != nil {
 		log.Printf("Error getting monitored users: %v", err)
@@ -117,52 +192,130 @@ func (b *Bot) dispatchMonitoringJobs(jobs chan<- []models.MonitoredUser) {
 
 	// Group users by UserID to deduplicate API calls
 	userGroups := make(map[string][]models.MonitoredUser)
+	monitored := make(map[string]bool)
 	for _, user := range users {
 		userGroups[user.UserID] = append(userGroups[user.UserID], user)
+		monitored[user.UserID] = true
 	}
 
+	b.persistCreatorHealth(monitored)
+
 	log.Printf("Dispatching %d unique users to %d workers.", len(userGroups), config.MonitorWorkerCount)
 
 	// Send each group of users as a single job to the workers channel.
+	follows := &followCache{}
 	for _, userEntries := range userGroups {
-		jobs <- userEntries
+		jobs <- monitoringJob{userEntries: userEntries, follows: follows}
 	}
 }
 
-// New worker function in bot.go
-func (b *Bot) worker(id int, jobs <-chan []models.MonitoredUser) {
+func (b *Bot) worker(id int, jobs <-chan monitoringJob) {
 	avatarRefreshDuration := int64(config.AvatarRefreshIntervalHours * 60 * 60)
 
-	for userEntries := range jobs {
+	for job := range jobs {
+		userEntries := job.userEntries
 		primaryUser := userEntries[0]
 
-		// Check if avatar needs refreshing
-		if time.Now().Unix()-primaryUser.AvatarLocationUpdatedAt > avatarRefreshDuration {
-			newAvatarLocation, err := b.refreshAvatarURL(primaryUser.Username)
-			if err != nil {
-				log.Printf("[Worker %d] Error refreshing avatar URL for %s: %v", id, primaryUser.Username, err)
-			} else {
-				// Update avatar for all entries of this user
-				for _, user := range userEntries {
-					err = b.Repo.UpdateAvatarInfo(user.GuildID, user.UserID, newAvatarLocation)
-					if err != nil {
-						log.Printf("[Worker %d] Error updating avatar URL in DB for %s in guild %s: %v", id, user.Username, user.GuildID, err)
-					}
-				}
-				// Update the avatar in memory for the current cycle's checks
-				for i := range userEntries {
-					userEntries[i].AvatarLocation = newAvatarLocation
-				}
-			}
+		// Check if the profile needs refreshing
+		if b.Clock.Now().Unix()-primaryUser.AvatarLocationUpdatedAt > avatarRefreshDuration {
+			b.refreshProfile(id, userEntries)
 		}
 
 		// Check live stream and posts. These API calls now happen in parallel for different users.
-		b.checkUserLiveStreamOptimized(userEntries)
-		b.checkUserPostsOptimized(userEntries)
+		liveErr := b.checkUserLiveStreamOptimized(userEntries)
+		postsErr := b.checkUserPostsOptimized(userEntries, job.follows)
+		b.health.recordCheck(primaryUser.UserID, errors.Join(liveErr, postsErr))
+	}
+}
+
+// refreshProfile reloads a creator's account info, stores the new avatar and
+// publishes AvatarChanged/UsernameChanged when they differ from what is stored.
+// The account is looked up by ID so renamed creators are still found.
+// userEntries is updated in place for the rest of the current cycle.
+func (b *Bot) refreshProfile(workerID int, userEntries []models.MonitoredUser) {
+	primaryUser := userEntries[0]
+
+	accountInfo, err := b.APIClient.GetAccountInfoByID(primaryUser.UserID)
+	if err != nil {
+		log.Printf("[Worker %d] Error refreshing profile for %s: %v", workerID, primaryUser.Username, err)
+		return
+	}
+
+	// A missing or unreadable avatar must not stop renames from being noticed.
+	// The stored avatar is kept and stamped so it isn't retried every cycle.
+	newAvatarLocation, err := avatarFromAccountInfo(accountInfo)
+	if err != nil {
+		log.Printf("[Worker %d] Error refreshing avatar URL for %s: %v", workerID, primaryUser.Username, err)
+		newAvatarLocation = primaryUser.AvatarLocation
+	}
+	b.refreshAvatar(workerID, userEntries, newAvatarLocation)
+
+	if accountInfo.Username != "" && accountInfo.Username != primaryUser.Username {
+		b.refreshUsername(workerID, userEntries, accountInfo.Username)
+	}
+}
+
+// refreshAvatar stores the creator's current avatar for every subscription
+// and publishes AvatarChanged when it differs from the stored one.
+func (b *Bot) refreshAvatar(workerID int, userEntries []models.MonitoredUser, newAvatarLocation string) {
+	primaryUser := userEntries[0]
+	now := b.Clock.Now().Unix()
+
+	// Update avatar for all entries of this user
+	for _, user := range userEntries {
+		err := b.Repo.UpdateAvatarInfo(user.GuildID, user.UserID, newAvatarLocation, now)
+		if err != nil {
+			log.Printf("[Worker %d] Error updating avatar URL in DB for %s in guild %s: %v", workerID, user.Username, user.GuildID, err)
+		}
+	}
+	// Update the avatar in memory for the current cycle's checks
+	for i := range userEntries {
+		userEntries[i].AvatarLocation = newAvatarLocation
+	}
+
+	if newAvatarLocation != primaryUser.AvatarLocation {
+		b.Events.Publish(events.AvatarChanged{
+			CreatorID:     primaryUser.UserID,
+			OldAvatar:     primaryUser.AvatarLocation,
+			NewAvatar:     newAvatarLocation,
+			Subscriptions: userEntries,
+		})
 	}
 }
 
-func (b *Bot) checkUserLiveStreamOptimized(userEntries []models.MonitoredUser) {
+// refreshUsername records the creator's old username as an alias, stores the
+// new one and publishes UsernameChanged.
+func (b *Bot) refreshUsername(workerID int, userEntries []models.MonitoredUser, newUsername string) {
+	primaryUser := userEntries[0]
+
+	// Remember the old name so commands keep accepting it.
+	err := b.Repo.AddCreatorAlias(&models.CreatorAlias{
+		UserID:     primaryUser.UserID,
+		Username:   primaryUser.Username,
+		ReplacedAt: b.Clock.Now().Unix(),
+	})
+	if err != nil {
+		log.Printf("[Worker %d] Error recording old username of %s: %v", workerID, newUsername, err)
+	}
+	if err := b.Repo.UpdateUsername(primaryUser.UserID, newUsername); err != nil {
+		log.Printf("[Worker %d] Error updating username for %s: %v", workerID, primaryUser.Username, err)
+		return
+	}
+	for i := range userEntries {
+		userEntries[i].Username = newUsername
+	}
+	b.Events.Publish(events.UsernameChanged{
+		CreatorID:     primaryUser.UserID,
+		OldUsername:   primaryUser.Username,
+		NewUsername:   newUsername,
+		Subscriptions: userEntries,
+	})
+}
+
+// checkUserLiveStreamOptimized detects streams starting and ending and publishes
+// StreamStarted/StreamEnded for the subscriptions that have live notifications enabled.
+// It returns the error of the stream info request, if any.
+func (b *Bot) checkUserLiveStreamOptimized(userEntries []models.MonitoredUser) error {
 	// Filter entries that have live notifications enabled
 	liveEnabledUsers := make([]models.MonitoredUser, 0)
 	for _, user := range userEntries {
@@ -172,7 +325,7 @@ func (b *Bot) checkUserLiveStreamOptimized(userEntries []models.MonitoredUser) {
 	}
 
 	if len(liveEnabledUsers) == 0 {
-		return
+		return nil
 	}
 
 	// Make API call only once
@@ -180,119 +333,155 @@ func (b *Bot) checkUserLiveStreamOptimized(userEntries []models.MonitoredUser) {
 	streamInfo, err := b.APIClient.GetStreamInfo(primaryUser.UserID)
 	if err != nil {
 		log.Printf("Error fetching stream info for %s: %v", primaryUser.Username, err)
-		return
+		return err
 	}
 
-	// Check if it's a new stream
-	if streamInfo.Response.Stream.Status == 2 && streamInfo.Response.Stream.StartedAt > primaryUser.LastStreamStart {
-		// Send notifications to all servers that have this user monitored with live enabled
+	stream := streamInfo.Response.Stream
+	if stream.Status == 2 {
+		// Check if it's a new stream
+		if stream.StartedAt <= primaryUser.LastStreamStart {
+			return nil
+		}
+
+		started := make([]models.MonitoredUser, 0, len(liveEnabledUsers))
 		for _, user := range liveEnabledUsers {
-			err = b.Repo.UpdateLastStreamStart(user.GuildID, user.UserID, streamInfo.Response.Stream.StartedAt)
+			err = b.Repo.UpdateLastStreamStart(user.GuildID, user.UserID, stream.StartedAt)
 			if err != nil {
 				log.Printf("Error updating last stream start: %v", err)
 				continue
 			}
-
-			embedMsg := embed.CreateLiveStreamEmbed(user.Username, streamInfo, user.AvatarLocation, user.LiveImageURL)
-
-			// If a role is set, create the mention string. Otherwise, it's empty.
-			var mention string
-			if user.LiveMentionRole != "" {
-				mention = fmt.Sprintf("<@&%s>", user.LiveMentionRole)
-			}
-
-			targetChannel := user.LiveNotificationChannel
-			if targetChannel == "" {
-				targetChannel = user.NotificationChannel
+			if err = b.Repo.UpdateIsLive(user.GuildID, user.UserID, true); err != nil {
+				log.Printf("Error updating live state for %s in guild %s: %v", user.Username, user.GuildID, err)
 			}
+			user.LastStreamStart = stream.StartedAt
+			user.IsLive = true
+			started = append(started, user)
+		}
 
-			_, err = b.Session.ChannelMessageSendComplex(targetChannel, &discordgo.MessageSend{
-				Content: mention,
-				Embed:   embedMsg,
+		if len(started) > 0 {
+			b.Events.Publish(events.StreamStarted{
+				CreatorID:     primaryUser.UserID,
+				Stream:        streamInfo,
+				Subscriptions: started,
 			})
-			if err != nil {
-				b.logNotificationError("live stream", user, targetChannel, err)
-			}
 		}
+		return nil
 	}
+
+	ended := make([]models.MonitoredUser, 0)
+	for _, user := range liveEnabledUsers {
+		if !user.IsLive {
+			continue
+		}
+		if err = b.Repo.UpdateIsLive(user.GuildID, user.UserID, false); err != nil {
+			log.Printf("Error updating live state for %s in guild %s: %v", user.Username, user.GuildID, err)
+			continue
+		}
+		user.IsLive = false
+		ended = append(ended, user)
+	}
+
+	if len(ended) > 0 {
+		b.Events.Publish(events.StreamEnded{
+			CreatorID:     primaryUser.UserID,
+			StartedAt:     primaryUser.LastStreamStart,
+			Subscriptions: ended,
+		})
+	}
+	return nil
 }
 
-func (b *Bot) checkUserPostsOptimized(userEntries []models.MonitoredUser) {
+// checkUserPostsOptimized detects a new latest post and publishes PostPublished
+// for the subscriptions that have not seen it yet. When the timeline can't be
+// read it follows the creator and, if that doesn't help, suspends post
+// notifications; suspended subscriptions are resumed once a periodic probe
+// reads the timeline again. It returns the error of the timeline request, if
+// any; missing timeline access is recorded separately.
+func (b *Bot) checkUserPostsOptimized(userEntries []models.MonitoredUser, follows *followCache) error {
 	// Filter entries that have post notifications enabled
 	postEnabledUsers := make([]models.MonitoredUser, 0)
+	suspendedUsers := make([]models.MonitoredUser, 0)
 	for _, user := range userEntries {
-		if user.PostsEnabled {
+		switch {
+		case !user.PostsEnabled:
+		case user.PostsSuspended:
+			suspendedUsers = append(suspendedUsers, user)
+		default:
 			postEnabledUsers = append(postEnabledUsers, user)
 		}
 	}
 
-	if len(postEnabledUsers) == 0 {
-		return
+	probing := len(suspendedUsers) > 0 && b.timelineProbeDue(userEntries[0].UserID)
+	if len(postEnabledUsers) == 0 && !probing {
+		return nil
 	}
 
 	// Make API call only once per unique user ID
-	primaryUser := postEnabledUsers[0]
+	primaryUser := userEntries[0]
 	latestPosts, err := b.APIClient.GetTimelinePost(primaryUser.UserID)
+	if isTimelineAccessError(err) {
+		log.Printf("No timeline access for %s, trying to follow: %v", primaryUser.Username, err)
+		b.followCreator(primaryUser.UserID, primaryUser.Username, follows)
+		latestPosts, err = b.APIClient.GetTimelinePost(primaryUser.UserID)
+	}
+	if isTimelineAccessError(err) {
+		log.Printf("Error fetching post info for %s: %v", primaryUser.Username, err)
+		b.health.recordTimeline(primaryUser.UserID, false, 0)
+		b.suspendPosts(postEnabledUsers)
+		return nil
+	}
 	if err != nil {
 		log.Printf("Error fetching post info for %s: %v", primaryUser.Username, err)
-		return
+		return err
+	}
+
+	if probing {
+		postEnabledUsers = append(postEnabledUsers, b.resumePosts(suspendedUsers)...)
 	}
 
 	// If there are no posts on the timeline at all, do nothing.
 	if len(latestPosts) == 0 {
-		return
+		b.health.recordTimeline(primaryUser.UserID, true, 0)
+		return nil
 	}
 
 	latestPost := latestPosts[0]
+	b.health.recordTimeline(primaryUser.UserID, true, latestPost.CreatedAt)
 
 	// Now, iterate through each server monitoring this user
+	unseen := make([]models.MonitoredUser, 0, len(postEnabledUsers))
 	for _, user := range postEnabledUsers {
 		// Check if this specific server has seen this post yet.
-		if latestPost.ID != user.LastPostID {
-			// This server needs a notification. First, update its state.
-			err := b.Repo.UpdateLastPostID(user.GuildID, user.UserID, latestPost.ID)
-			if err != nil {
-				log.Printf("Error updating last post ID for %s in guild %s: %v", user.Username, user.GuildID, err)
-				continue // Skip this server if DB update fails
-			}
-
-			// This flag is still useful for logging, but we won't use it to suppress the ping.
-			isFirstPostForThisServer := user.LastPostID == "" || user.LastPostID == "0"
-
-			// Pass nil for postMedia, as we are no longer fetching it.
-			embedMsg := embed.CreatePostEmbed(user.Username, latestPost, user.AvatarLocation, nil)
-
-			// If a role is set, create the mention string. Otherwise, it's empty.
-			var mention string
-			if user.PostMentionRole != "" {
-				mention = fmt.Sprintf("<@&%s>", user.PostMentionRole)
-			}
-
-			targetChannel := user.PostNotificationChannel
-			if targetChannel == "" {
-				targetChannel = user.NotificationChannel
-			}
-
-			log.Printf("Sending post notification for %s to guild %s. First post: %t", user.Username, user.GuildID, isFirstPostForThisServer)
+		if latestPost.ID == user.LastPostID {
+			continue
+		}
 
-			_, err = b.Session.ChannelMessageSendComplex(targetChannel, &discordgo.MessageSend{
-				Content: mention,
-				Embed:   embedMsg,
-			})
-			if err != nil {
-				b.logNotificationError("post", user, targetChannel, err)
-			}
+		// This server needs a notification. First, update its state.
+		err := b.Repo.UpdateLastPostID(user.GuildID, user.UserID, latestPost.ID)
+		if err != nil {
+			log.Printf("Error updating last post ID for %s in guild %s: %v", user.Username, user.GuildID, err)
+			continue // Skip this server if DB update fails
 		}
+		unseen = append(unseen, user)
 	}
+
+	if len(unseen) > 0 {
+		b.Events.Publish(events.PostPublished{
+			CreatorID:     primaryUser.UserID,
+			Post:          latestPost,
+			Subscriptions: unseen,
+		})
+	}
+	return nil
 }
 
 func (b *Bot) logNotificationError(notificationType string, user models.MonitoredUser, targetChannel string, err error) {
-	guild, _ := b.Session.Guild(user.GuildID)
+	guild, _ := b.Discord.Guild(user.GuildID)
 	guildName := "Unknown Server"
 	if guild != nil {
 		guildName = guild.Name
 	}
-	channel, _ := b.Session.Channel(targetChannel)
+	channel, _ := b.Discord.Channel(targetChannel)
 	channelName := "Unknown Channel"
 	if channel != nil {
 		channelName = channel.Name
@@ -314,17 +503,13 @@ func (b *Bot) updateStatusPeriodically() {
 
 	for range ticker.C {
 		b.updateBotStatus()
+		log.Printf("Event counts since start: %s", b.metrics.summary())
 	}
 }
 
-func (b *Bot) refreshAvatarURL(username string) (string, error) {
-	accountInfo, err := b.APIClient.GetAccountInfo(username)
-	if err != nil {
-		return "", err
-	}
-
+func avatarFromAccountInfo(accountInfo *api.ModelAccountInfo) (string, error) {
 	if accountInfo == nil || accountInfo.Avatar.Locations == nil || len(accountInfo.Avatar.Variants) == 0 || len(accountInfo.Avatar.Variants[0].Locations) == 0 {
-		return "", fmt.Errorf("invalid account info structure for user %s", username)
+		return "", fmt.Errorf("invalid account info structure")
 	}
 
 	return accountInfo.Avatar.Variants[0].Locations[0].Location, nil
@@ -0,0 +1,444 @@
+package bottest
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/bot"
+)
+
+// Message is a message the bot sent through the fake.
+type Message struct {
+	ID        string
+	ChannelID string
+	Content   string
+	Embed     *discordgo.MessageEmbed
+	// Username is set for messages sent through a webhook.
+	Username string
+}
+
+// Thread is a thread the bot started on a message or in a forum.
+type Thread struct {
+	ID        string
+	ParentID  string
+	MessageID string
+	Name      string
+}
+
+// FakeDiscord implements bot.DiscordAPI in memory and records everything the
+// bot sends. Guilds and channels must be added before the bot uses them;
+// unknown ones return the same errors as Discord.
+type FakeDiscord struct {
+	mu sync.Mutex
+
+	guilds          map[string]*discordgo.Guild
+	channels        map[string]*discordgo.Channel
+	webhooks        map[string][]*discordgo.Webhook
+	scheduledEvents map[string]*discordgo.GuildScheduledEvent
+	memberRoles     map[string][]string // "<guild ID>/<user ID>" to role IDs
+
+	messages   []Message
+	threads    []Thread
+	crossposts []string
+	nextID     int
+}
+
+var _ bot.DiscordAPI = (*FakeDiscord)(nil)
+
+func NewFakeDiscord() *FakeDiscord {
+	return &FakeDiscord{
+		guilds:          make(map[string]*discordgo.Guild),
+		channels:        make(map[string]*discordgo.Channel),
+		webhooks:        make(map[string][]*discordgo.Webhook),
+		scheduledEvents: make(map[string]*discordgo.GuildScheduledEvent),
+		memberRoles:     make(map[string][]string),
+	}
+}
+
+// AddGuild registers a guild if it doesn't exist yet.
+func (d *FakeDiscord) AddGuild(guildID, name string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.guilds[guildID]; !ok {
+		d.guilds[guildID] = &discordgo.Guild{ID: guildID, Name: name}
+	}
+}
+
+// AddChannel registers a channel of the given type in a guild.
+func (d *FakeDiscord) AddChannel(guildID, channelID string, channelType discordgo.ChannelType) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.channels[channelID] = &discordgo.Channel{ID: channelID, GuildID: guildID, Name: channelID, Type: channelType}
+}
+
+// Messages returns the messages sent so far, oldest first.
+func (d *FakeDiscord) Messages() []Message {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return slices.Clone(d.messages)
+}
+
+// Threads returns the threads started so far, oldest first.
+func (d *FakeDiscord) Threads() []Thread {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return slices.Clone(d.threads)
+}
+
+// Crossposts returns the IDs of the messages published so far.
+func (d *FakeDiscord) Crossposts() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return slices.Clone(d.crossposts)
+}
+
+// Roles returns the roles of a guild.
+func (d *FakeDiscord) Roles(guildID string) []*discordgo.Role {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if guild, ok := d.guilds[guildID]; ok {
+		return slices.Clone(guild.Roles)
+	}
+	return nil
+}
+
+// MemberRoles returns the IDs of the roles the bot gave a member.
+func (d *FakeDiscord) MemberRoles(guildID, userID string) []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return slices.Clone(d.memberRoles[guildID+"/"+userID])
+}
+
+// Reset forgets everything sent so far, keeping guilds and channels.
+func (d *FakeDiscord) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.messages = nil
+	d.threads = nil
+	d.crossposts = nil
+}
+
+func (d *FakeDiscord) newID() string {
+	d.nextID++
+	return fmt.Sprintf("%d", d.nextID)
+}
+
+// notFound builds the REST error Discord returns for unknown resources.
+func notFound(code int, message string) error {
+	return &discordgo.RESTError{
+		Response: &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found"},
+		Message:  &discordgo.APIErrorMessage{Code: code, Message: message},
+	}
+}
+
+func (d *FakeDiscord) channel(channelID string) (*discordgo.Channel, error) {
+	channel, ok := d.channels[channelID]
+	if !ok {
+		return nil, notFound(discordgo.ErrCodeUnknownChannel, "Unknown Channel")
+	}
+	return channel, nil
+}
+
+func (d *FakeDiscord) send(channelID, content string, embeds []*discordgo.MessageEmbed, username string) *discordgo.Message {
+	msg := Message{ID: d.newID(), ChannelID: channelID, Content: content, Username: username}
+	if len(embeds) > 0 {
+		msg.Embed = embeds[0]
+	}
+	d.messages = append(d.messages, msg)
+	return &discordgo.Message{ID: msg.ID, ChannelID: channelID, Content: content, Embeds: embeds}
+}
+
+func (d *FakeDiscord) Guild(guildID string, options ...discordgo.RequestOption) (*discordgo.Guild, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	guild, ok := d.guilds[guildID]
+	if !ok {
+		return nil, notFound(discordgo.ErrCodeUnknownGuild, "Unknown Guild")
+	}
+	return guild, nil
+}
+
+func (d *FakeDiscord) GuildMember(guildID, userID string, options ...discordgo.RequestOption) (*discordgo.Member, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.guilds[guildID]; !ok {
+		return nil, notFound(discordgo.ErrCodeUnknownGuild, "Unknown Guild")
+	}
+	return &discordgo.Member{GuildID: guildID, User: &discordgo.User{ID: userID}}, nil
+}
+
+func (d *FakeDiscord) Channel(channelID string, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.channel(channelID)
+}
+
+func (d *FakeDiscord) ChannelEditComplex(channelID string, data *discordgo.ChannelEdit, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	channel, err := d.channel(channelID)
+	if err != nil {
+		return nil, err
+	}
+	if data.AvailableTags != nil {
+		channel.AvailableTags = *data.AvailableTags
+		for idx := range channel.AvailableTags {
+			if channel.AvailableTags[idx].ID == "" {
+				channel.AvailableTags[idx].ID = d.newID()
+			}
+		}
+	}
+	if data.AppliedTags != nil {
+		channel.AppliedTags = *data.AppliedTags
+	}
+	return channel, nil
+}
+
+func (d *FakeDiscord) ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, err := d.channel(channelID); err != nil {
+		return nil, err
+	}
+	embeds := data.Embeds
+	if data.Embed != nil {
+		embeds = append([]*discordgo.MessageEmbed{data.Embed}, embeds...)
+	}
+	return d.send(channelID, data.Content, embeds, ""), nil
+}
+
+func (d *FakeDiscord) ChannelMessageCrosspost(channelID, messageID string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, err := d.channel(channelID); err != nil {
+		return nil, err
+	}
+	d.crossposts = append(d.crossposts, messageID)
+	return &discordgo.Message{ID: messageID, ChannelID: channelID}, nil
+}
+
+// startThread creates the thread channel. d.mu must be held.
+func (d *FakeDiscord) startThread(parent *discordgo.Channel, messageID, name string, appliedTags []string) *discordgo.Channel {
+	thread := &discordgo.Channel{
+		ID:          d.newID(),
+		GuildID:     parent.GuildID,
+		ParentID:    parent.ID,
+		Name:        name,
+		Type:        discordgo.ChannelTypeGuildPublicThread,
+		AppliedTags: appliedTags,
+	}
+	d.channels[thread.ID] = thread
+	d.threads = append(d.threads, Thread{ID: thread.ID, ParentID: parent.ID, MessageID: messageID, Name: name})
+	return thread
+}
+
+func (d *FakeDiscord) ForumThreadStartComplex(channelID string, threadData *discordgo.ThreadStart, messageData *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	forum, err := d.channel(channelID)
+	if err != nil {
+		return nil, err
+	}
+	thread := d.startThread(forum, "", threadData.Name, threadData.AppliedTags)
+	embeds := messageData.Embeds
+	if messageData.Embed != nil {
+		embeds = append([]*discordgo.MessageEmbed{messageData.Embed}, embeds...)
+	}
+	d.send(thread.ID, messageData.Content, embeds, "")
+	return thread, nil
+}
+
+func (d *FakeDiscord) MessageThreadStartComplex(channelID, messageID string, data *discordgo.ThreadStart, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	parent, err := d.channel(channelID)
+	if err != nil {
+		return nil, err
+	}
+	return d.startThread(parent, messageID, data.Name, nil), nil
+}
+
+// UserChannelCreate opens a DM channel with the member, named "dm-<user ID>".
+func (d *FakeDiscord) UserChannelCreate(recipientID string, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	channelID := "dm-" + recipientID
+	channel, ok := d.channels[channelID]
+	if !ok {
+		channel = &discordgo.Channel{ID: channelID, Type: discordgo.ChannelTypeDM}
+		d.channels[channelID] = channel
+	}
+	return channel, nil
+}
+
+func (d *FakeDiscord) ChannelWebhooks(channelID string, options ...discordgo.RequestOption) ([]*discordgo.Webhook, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, err := d.channel(channelID); err != nil {
+		return nil, err
+	}
+	return slices.Clone(d.webhooks[channelID]), nil
+}
+
+func (d *FakeDiscord) WebhookCreate(channelID, name, avatar string, options ...discordgo.RequestOption) (*discordgo.Webhook, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	channel, err := d.channel(channelID)
+	if err != nil {
+		return nil, err
+	}
+	webhook := &discordgo.Webhook{ID: d.newID(), ChannelID: channelID, GuildID: channel.GuildID, Name: name, Token: d.newID()}
+	d.webhooks[channelID] = append(d.webhooks[channelID], webhook)
+	return webhook, nil
+}
+
+// webhook finds a webhook by ID and token. d.mu must be held.
+func (d *FakeDiscord) webhook(webhookID, token string) (*discordgo.Webhook, error) {
+	for _, webhooks := range d.webhooks {
+		for _, webhook := range webhooks {
+			if webhook.ID == webhookID && webhook.Token == token {
+				return webhook, nil
+			}
+		}
+	}
+	return nil, notFound(discordgo.ErrCodeUnknownWebhook, "Unknown Webhook")
+}
+
+func (d *FakeDiscord) WebhookExecute(webhookID, token string, wait bool, data *discordgo.WebhookParams, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	webhook, err := d.webhook(webhookID, token)
+	if err != nil {
+		return nil, err
+	}
+	channel, err := d.channel(webhook.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	if channel.Type == discordgo.ChannelTypeGuildForum {
+		thread := d.startThread(channel, "", data.ThreadName, nil)
+		return d.send(thread.ID, data.Content, data.Embeds, data.Username), nil
+	}
+	return d.send(channel.ID, data.Content, data.Embeds, data.Username), nil
+}
+
+func (d *FakeDiscord) WebhookThreadExecute(webhookID, token string, wait bool, threadID string, data *discordgo.WebhookParams, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, err := d.webhook(webhookID, token); err != nil {
+		return nil, err
+	}
+	if _, err := d.channel(threadID); err != nil {
+		return nil, err
+	}
+	return d.send(threadID, data.Content, data.Embeds, data.Username), nil
+}
+
+func (d *FakeDiscord) GuildScheduledEvent(guildID, eventID string, userCount bool, options ...discordgo.RequestOption) (*discordgo.GuildScheduledEvent, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	event, ok := d.scheduledEvents[eventID]
+	if !ok || event.GuildID != guildID {
+		return nil, notFound(discordgo.ErrCodeUnknownGuildScheduledEvent, "Unknown Guild Scheduled Event")
+	}
+	return event, nil
+}
+
+func (d *FakeDiscord) GuildScheduledEventCreate(guildID string, params *discordgo.GuildScheduledEventParams, options ...discordgo.RequestOption) (*discordgo.GuildScheduledEvent, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.guilds[guildID]; !ok {
+		return nil, notFound(discordgo.ErrCodeUnknownGuild, "Unknown Guild")
+	}
+	event := &discordgo.GuildScheduledEvent{
+		ID:          d.newID(),
+		GuildID:     guildID,
+		Name:        params.Name,
+		Description: params.Description,
+		Status:      discordgo.GuildScheduledEventStatusScheduled,
+	}
+	d.scheduledEvents[event.ID] = event
+	return event, nil
+}
+
+func (d *FakeDiscord) GuildScheduledEventEdit(guildID, eventID string, params *discordgo.GuildScheduledEventParams, options ...discordgo.RequestOption) (*discordgo.GuildScheduledEvent, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	event, ok := d.scheduledEvents[eventID]
+	if !ok || event.GuildID != guildID {
+		return nil, notFound(discordgo.ErrCodeUnknownGuildScheduledEvent, "Unknown Guild Scheduled Event")
+	}
+	if params.Status != 0 {
+		event.Status = params.Status
+	}
+	return event, nil
+}
+
+// role finds a role of a guild. d.mu must be held.
+func (d *FakeDiscord) role(guildID, roleID string) (*discordgo.Guild, int, error) {
+	guild, ok := d.guilds[guildID]
+	if !ok {
+		return nil, 0, notFound(discordgo.ErrCodeUnknownGuild, "Unknown Guild")
+	}
+	idx := slices.IndexFunc(guild.Roles, func(role *discordgo.Role) bool { return role.ID == roleID })
+	if idx < 0 {
+		return nil, 0, notFound(discordgo.ErrCodeUnknownRole, "Unknown Role")
+	}
+	return guild, idx, nil
+}
+
+func (d *FakeDiscord) GuildRoleCreate(guildID string, data *discordgo.RoleParams, options ...discordgo.RequestOption) (*discordgo.Role, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	guild, ok := d.guilds[guildID]
+	if !ok {
+		return nil, notFound(discordgo.ErrCodeUnknownGuild, "Unknown Guild")
+	}
+	role := &discordgo.Role{ID: d.newID(), Name: data.Name}
+	if data.Mentionable != nil {
+		role.Mentionable = *data.Mentionable
+	}
+	guild.Roles = append(guild.Roles, role)
+	return role, nil
+}
+
+func (d *FakeDiscord) GuildRoleDelete(guildID, roleID string, options ...discordgo.RequestOption) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	guild, idx, err := d.role(guildID, roleID)
+	if err != nil {
+		return err
+	}
+	guild.Roles = slices.Delete(guild.Roles, idx, idx+1)
+	for key, roles := range d.memberRoles {
+		d.memberRoles[key] = slices.DeleteFunc(roles, func(id string) bool { return id == roleID })
+	}
+	return nil
+}
+
+func (d *FakeDiscord) GuildMemberRoleAdd(guildID, userID, roleID string, options ...discordgo.RequestOption) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, _, err := d.role(guildID, roleID); err != nil {
+		return err
+	}
+	key := guildID + "/" + userID
+	if !slices.Contains(d.memberRoles[key], roleID) {
+		d.memberRoles[key] = append(d.memberRoles[key], roleID)
+	}
+	return nil
+}
+
+func (d *FakeDiscord) GuildMemberRoleRemove(guildID, userID, roleID string, options ...discordgo.RequestOption) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, _, err := d.role(guildID, roleID); err != nil {
+		return err
+	}
+	key := guildID + "/" + userID
+	d.memberRoles[key] = slices.DeleteFunc(d.memberRoles[key], func(id string) bool { return id == roleID })
+	return nil
+}
@@ -0,0 +1,279 @@
+package bottest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/fvckgrimm/discord-fansly-notify/api"
+	"golang.org/x/time/rate"
+)
+
+// FanslyServer fakes the Fansly API endpoints the bot uses: account lookups
+// by username or ID, follows, timelines and stream status. Creators and their activity
+// are set up by the test; unknown creators have an empty timeline and no
+// stream.
+type FanslyServer struct {
+	*httptest.Server
+
+	mu                sync.Mutex
+	creators          map[string]*fakeCreator
+	followingRequests int
+}
+
+type fakeCreator struct {
+	id             string
+	username       string
+	avatar         string
+	posts          []api.Post // newest first, like the real timeline
+	streamStatus   int
+	streamStart    int64
+	timelineDenied bool
+	followersOnly  bool
+	followed       bool
+}
+
+// NewFanslyServer starts the fake API. Close it when the test is done.
+func NewFanslyServer() *FanslyServer {
+	f := &FanslyServer{creators: make(map[string]*fakeCreator)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/account", f.handleAccounts)
+	mux.HandleFunc("GET /api/v1/account/me", f.handleMe)
+	mux.HandleFunc("GET /api/v1/account/{id}/following", f.handleFollowing)
+	mux.HandleFunc("POST /api/v1/account/{id}/followers", f.handleFollow)
+	mux.HandleFunc("DELETE /api/v1/account/{id}/followers", f.handleUnfollow)
+	mux.HandleFunc("GET /api/v1/timelinenew/{id}", f.handleTimeline)
+	mux.HandleFunc("GET /api/v1/streaming/channel/{id}", f.handleStream)
+	f.Server = httptest.NewServer(mux)
+	return f
+}
+
+// Client returns an API client talking to the fake without rate limiting.
+func (f *FanslyServer) Client() *api.Client {
+	return &api.Client{
+		HTTPClient: f.Server.Client(),
+		BaseURL:    f.URL,
+		Limiter:    rate.NewLimiter(rate.Inf, 1),
+	}
+}
+
+// AddCreator registers a creator account with an avatar URL. An empty avatar
+// makes the account come back without one.
+func (f *FanslyServer) AddCreator(id, username, avatar string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c := f.creator(id)
+	c.username = username
+	c.avatar = avatar
+}
+
+func (f *FanslyServer) creator(id string) *fakeCreator {
+	c, ok := f.creators[id]
+	if !ok {
+		c = &fakeCreator{id: id}
+		f.creators[id] = c
+	}
+	return c
+}
+
+// Rename changes the creator's username. Lookups by ID keep finding them.
+func (f *FanslyServer) Rename(creatorID, username string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.creator(creatorID).username = username
+}
+
+// Post adds a post to the top of the creator's timeline.
+func (f *FanslyServer) Post(creatorID string, post api.Post) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c := f.creator(creatorID)
+	c.posts = append([]api.Post{post}, c.posts...)
+}
+
+// StartStream marks the creator as live since startedAt, in milliseconds.
+func (f *FanslyServer) StartStream(creatorID string, startedAt int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c := f.creator(creatorID)
+	c.streamStatus = 2
+	c.streamStart = startedAt
+}
+
+// EndStream marks the creator as offline.
+func (f *FanslyServer) EndStream(creatorID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.creator(creatorID).streamStatus = 0
+}
+
+// DenyTimeline makes the creator's timeline unreadable, as if it required a
+// subscription the bot's account doesn't have. Following doesn't help.
+func (f *FanslyServer) DenyTimeline(creatorID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.creator(creatorID).timelineDenied = true
+}
+
+// AllowTimeline undoes DenyTimeline.
+func (f *FanslyServer) AllowTimeline(creatorID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.creator(creatorID).timelineDenied = false
+}
+
+// RequireFollow makes the creator's timeline readable only while the bot's
+// account follows them, and unfollows them.
+func (f *FanslyServer) RequireFollow(creatorID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c := f.creator(creatorID)
+	c.followersOnly = true
+	c.followed = false
+}
+
+// Follow makes the bot's account follow the creator, as if the owner had done
+// it by hand.
+func (f *FanslyServer) Follow(creatorID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.creator(creatorID).followed = true
+}
+
+// Followed reports whether the bot's account follows the creator.
+func (f *FanslyServer) Followed(creatorID string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.creator(creatorID).followed
+}
+
+// FollowingRequests returns how many pages of the bot's following list have
+// been requested.
+func (f *FanslyServer) FollowingRequests() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.followingRequests
+}
+
+func writeJSON(w http.ResponseWriter, response any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"success": true, "response": response})
+}
+
+func (f *FanslyServer) handleAccounts(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	query := r.URL.Query()
+	accounts := []map[string]any{}
+	for _, c := range f.creators {
+		if c.username == "" {
+			continue
+		}
+		matches := func(value string) bool { return strings.EqualFold(value, c.username) }
+		if query.Has("ids") {
+			matches = func(value string) bool { return value == c.id }
+		}
+		if !slices.ContainsFunc(strings.Split(query.Get("usernames")+query.Get("ids"), ","), matches) {
+			continue
+		}
+
+		account := map[string]any{"id": c.id, "username": c.username}
+		if c.avatar != "" {
+			locations := []map[string]string{{"location": c.avatar}}
+			account["avatar"] = map[string]any{
+				"locations": locations,
+				"variants":  []map[string]any{{"locations": locations}},
+			}
+		}
+		accounts = append(accounts, account)
+	}
+	writeJSON(w, accounts)
+}
+
+func (f *FanslyServer) handleTimeline(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c := f.creator(r.PathValue("id"))
+	if c.timelineDenied || (c.followersOnly && !c.followed) {
+		// The timeline requires a flag the account doesn't have.
+		writeJSON(w, map[string]any{
+			"posts":                              []api.Post{},
+			"timelineReadPermissionFlags":        []map[string]any{{"accountId": c.id, "flags": 2}},
+			"accountTimelineReadPermissionFlags": map[string]any{"flags": 0},
+		})
+		return
+	}
+	writeJSON(w, map[string]any{"posts": append([]api.Post{}, c.posts...)})
+}
+
+func (f *FanslyServer) handleStream(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c := f.creator(r.PathValue("id"))
+	stream := map[string]any{"status": c.streamStatus}
+	if c.streamStatus == 2 {
+		stream["startedAt"] = c.streamStart
+		stream["access"] = true
+	}
+	writeJSON(w, map[string]any{"stream": stream})
+}
+
+// botAccountID is the ID of the account the fake API client is logged in as.
+const botAccountID = "1"
+
+func (f *FanslyServer) handleMe(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]any{"account": map[string]any{"id": botAccountID}})
+}
+
+func (f *FanslyServer) handleFollowing(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.followingRequests++
+
+	var ids []string
+	for _, c := range f.creators {
+		if c.followed {
+			ids = append(ids, c.id)
+		}
+	}
+	slices.Sort(ids)
+
+	// Page like the real API so clients that trust a single request miss follows.
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	if limit <= 0 {
+		limit = 25
+	}
+	ids = ids[min(offset, len(ids)):min(offset+limit, len(ids))]
+
+	following := []map[string]any{}
+	for _, id := range ids {
+		following = append(following, map[string]any{"accountId": id})
+	}
+	writeJSON(w, following)
+}
+
+func (f *FanslyServer) handleFollow(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.creator(r.PathValue("id")).followed = true
+	writeJSON(w, map[string]any{})
+}
+
+func (f *FanslyServer) handleUnfollow(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.creator(r.PathValue("id")).followed = false
+	writeJSON(w, map[string]any{})
+}
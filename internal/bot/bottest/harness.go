@@ -0,0 +1,106 @@
+// Package bottest runs the bot end to end against a fake Fansly API, a fake
+// Discord and an in-memory store. Scenarios are built on NewHarness; the
+// monitoring scenarios live in this package's tests.
+package bottest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/bot"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/database"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+// Harness is a bot wired to fakes only.
+type Harness struct {
+	Bot     *bot.Bot
+	Discord *FakeDiscord
+	Fansly  *FanslyServer
+	Store   *database.MemoryStore
+	Clock   *Clock
+}
+
+// Notification is the part of a sent message the scenarios compare.
+type Notification struct {
+	ChannelID   string
+	Content     string
+	Title       string
+	URL         string
+	Description string
+}
+
+// startTime is where the harness clock starts, so runs don't depend on the
+// wall clock.
+var startTime = time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+// NewHarness builds a bot on fresh fakes. The fake Fansly server is closed
+// when the test ends.
+func NewHarness(t testing.TB) *Harness {
+	t.Helper()
+
+	session, err := discordgo.New("Bot test")
+	if err != nil {
+		t.Fatalf("creating session: %v", err)
+	}
+
+	h := &Harness{
+		Discord: NewFakeDiscord(),
+		Fansly:  NewFanslyServer(),
+		Store:   database.NewMemoryStore(),
+		Clock:   NewClock(startTime),
+	}
+	t.Cleanup(h.Fansly.Close)
+
+	h.Bot = bot.NewWithDependencies(bot.Dependencies{
+		Session:   session,
+		Discord:   h.Discord,
+		APIClient: h.Fansly.Client(),
+		Store:     h.Store,
+		Clock:     h.Clock,
+	})
+	return h
+}
+
+// Monitor registers the subscription's guild and channels with the fake
+// Discord and stores it. The avatar is marked fresh so the cycle doesn't
+// refresh the profile unless the test clears AvatarLocationUpdatedAt.
+func (h *Harness) Monitor(t testing.TB, user models.MonitoredUser) {
+	t.Helper()
+
+	if user.AvatarLocationUpdatedAt == 0 {
+		user.AvatarLocationUpdatedAt = h.Clock.Now().Unix()
+	}
+
+	h.Discord.AddGuild(user.GuildID, user.GuildID)
+	for _, channelID := range []string{user.NotificationChannel, user.PostNotificationChannel, user.LiveNotificationChannel} {
+		if channelID != "" {
+			h.Discord.AddChannel(user.GuildID, channelID, discordgo.ChannelTypeGuildText)
+		}
+	}
+
+	if err := h.Store.AddMonitoredUser(&user); err != nil {
+		t.Fatalf("storing subscription for %s in guild %s: %v", user.Username, user.GuildID, err)
+	}
+}
+
+// RunCycle runs one monitoring cycle and returns the messages it sent, in
+// the order they were sent.
+func (h *Harness) RunCycle() []Notification {
+	before := len(h.Discord.Messages())
+	h.Bot.RunMonitoringCycle()
+
+	sent := h.Discord.Messages()[before:]
+	notifications := make([]Notification, 0, len(sent))
+	for _, msg := range sent {
+		n := Notification{ChannelID: msg.ChannelID, Content: msg.Content}
+		if msg.Embed != nil {
+			n.Title = msg.Embed.Title
+			n.URL = msg.Embed.URL
+			n.Description = msg.Embed.Description
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications
+}
@@ -0,0 +1,351 @@
+package bottest
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fvckgrimm/discord-fansly-notify/api"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/config"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+const (
+	creatorID   = "100"
+	creatorName = "creator"
+	avatarURL   = "https://cdn.example/avatar.png"
+)
+
+// subscription returns a subscription with posts and live enabled, using
+// separate post and live channels named after the guild.
+func subscription(guildID string) models.MonitoredUser {
+	return models.MonitoredUser{
+		GuildID:                 guildID,
+		UserID:                  creatorID,
+		Username:                creatorName,
+		NotificationChannel:     guildID + "-posts",
+		PostNotificationChannel: guildID + "-posts",
+		LiveNotificationChannel: guildID + "-live",
+		AvatarLocation:          avatarURL,
+		PostsEnabled:            true,
+		LiveEnabled:             true,
+		PostMentionRole:         guildID + "-post-role",
+		LiveMentionRole:         guildID + "-live-role",
+		ThreadLive:              true,
+	}
+}
+
+func newScenario(t *testing.T) *Harness {
+	h := NewHarness(t)
+	h.Fansly.AddCreator(creatorID, creatorName, avatarURL)
+	return h
+}
+
+func postNotification(guildID string, post api.Post) Notification {
+	return Notification{
+		ChannelID:   guildID + "-posts",
+		Content:     fmt.Sprintf("<@&%s-post-role>", guildID),
+		Title:       "New post from " + creatorName,
+		URL:         "https://fans.ly/post/" + post.ID,
+		Description: post.Content,
+	}
+}
+
+func liveNotification(guildID string) Notification {
+	return Notification{
+		ChannelID:   guildID + "-live",
+		Content:     fmt.Sprintf("<@&%s-live-role>", guildID),
+		Title:       "Stream Live!",
+		URL:         "https://fansly.com/live/" + creatorName,
+		Description: creatorName + " is now live on Fansly!",
+	}
+}
+
+// expectNotifications compares sent notifications regardless of order, since
+// guilds are notified in store order.
+func expectNotifications(t *testing.T, got []Notification, want ...Notification) {
+	t.Helper()
+
+	byChannel := func(a, b Notification) int {
+		return strings.Compare(a.ChannelID+a.URL, b.ChannelID+b.URL)
+	}
+	got = slices.Clone(got)
+	want = slices.Clone(want)
+	slices.SortFunc(got, byChannel)
+	slices.SortFunc(want, byChannel)
+
+	if !slices.Equal(got, want) {
+		t.Errorf("notifications:\n got  %+v\n want %+v", got, want)
+	}
+}
+
+func TestNewPost(t *testing.T) {
+	h := newScenario(t)
+	h.Monitor(t, subscription("guild-1"))
+
+	expectNotifications(t, h.RunCycle())
+
+	post := api.Post{ID: "1001", Content: "Hello there", CreatedAt: 1700000000}
+	h.Fansly.Post(creatorID, post)
+	expectNotifications(t, h.RunCycle(), postNotification("guild-1", post))
+
+	// The post was recorded as seen, so it is not announced again.
+	expectNotifications(t, h.RunCycle())
+}
+
+func TestMultiplePosts(t *testing.T) {
+	h := newScenario(t)
+	h.Monitor(t, subscription("guild-1"))
+
+	h.Fansly.Post(creatorID, api.Post{ID: "1001", Content: "First", CreatedAt: 1700000000})
+	h.Fansly.Post(creatorID, api.Post{ID: "1002", Content: "Second", CreatedAt: 1700000060})
+	latest := api.Post{ID: "1003", Content: "Third", CreatedAt: 1700000120}
+	h.Fansly.Post(creatorID, latest)
+
+	// Only the newest post between two cycles is announced.
+	expectNotifications(t, h.RunCycle(), postNotification("guild-1", latest))
+	expectNotifications(t, h.RunCycle())
+}
+
+func TestStreamStart(t *testing.T) {
+	h := newScenario(t)
+	h.Monitor(t, subscription("guild-1"))
+
+	h.Fansly.StartStream(creatorID, 1700000000000)
+	expectNotifications(t, h.RunCycle(), liveNotification("guild-1"))
+
+	threads := h.Discord.Threads()
+	if len(threads) != 1 || threads[0].ParentID != "guild-1-live" || threads[0].Name != creatorName+" is live" {
+		t.Errorf("threads = %+v, want one %q thread in guild-1-live", threads, creatorName+" is live")
+	}
+
+	// The same stream is not announced twice, and its end sends nothing.
+	expectNotifications(t, h.RunCycle())
+	h.Fansly.EndStream(creatorID)
+	expectNotifications(t, h.RunCycle())
+
+	stored, err := h.Store.GetMonitoredUser("guild-1", creatorID)
+	if err != nil || stored == nil {
+		t.Fatalf("GetMonitoredUser: %v, %v", stored, err)
+	}
+	if stored.IsLive {
+		t.Errorf("subscription still marked live after the stream ended")
+	}
+}
+
+func TestLiveDisabledGuild(t *testing.T) {
+	h := newScenario(t)
+	h.Monitor(t, subscription("guild-1"))
+	quiet := subscription("guild-2")
+	quiet.LiveEnabled = false
+	h.Monitor(t, quiet)
+
+	h.Fansly.StartStream(creatorID, 1700000000000)
+	post := api.Post{ID: "1001", Content: "Going live soon", CreatedAt: 1700000000}
+	h.Fansly.Post(creatorID, post)
+
+	// Posts still reach the guild that turned live notifications off.
+	expectNotifications(t, h.RunCycle(),
+		liveNotification("guild-1"),
+		postNotification("guild-1", post),
+		postNotification("guild-2", post),
+	)
+}
+
+func TestTimelineDenied(t *testing.T) {
+	h := newScenario(t)
+	h.Monitor(t, subscription("guild-1"))
+
+	h.Fansly.Post(creatorID, api.Post{ID: "1001", Content: "Subscribers only", CreatedAt: 1700000000})
+	h.Fansly.DenyTimeline(creatorID)
+	h.Fansly.StartStream(creatorID, 1700000000000)
+
+	// A timeline the bot can't read doesn't stop live notifications, but
+	// posts are paused with a notice.
+	expectNotifications(t, h.RunCycle(),
+		liveNotification("guild-1"),
+		Notification{
+			ChannelID: "guild-1-posts",
+			Content: "⚠️ The bot's Fansly account can no longer see **" + creatorName + "**'s posts, so only live notifications will be sent for now. " +
+				"Post notifications turn back on by themselves once the account follows or subscribes to them again.",
+		},
+	)
+	stored, err := h.Store.GetMonitoredUser("guild-1", creatorID)
+	if err != nil || stored == nil || !stored.PostsEnabled || !stored.PostsSuspended {
+		t.Fatalf("subscription after losing access = %+v, %v; want posts suspended", stored, err)
+	}
+
+	// The notice is sent once.
+	expectNotifications(t, h.RunCycle())
+
+	// Once the timeline is readable again, posts resume with the latest post.
+	h.Fansly.AllowTimeline(creatorID)
+	post := api.Post{ID: "1002", Content: "Back again", CreatedAt: 1700000600}
+	h.Fansly.Post(creatorID, post)
+	h.Clock.Advance(24 * time.Hour)
+	expectNotifications(t, h.RunCycle(),
+		Notification{
+			ChannelID: "guild-1-posts",
+			Content:   "✅ The bot can see **" + creatorName + "**'s posts again, so post notifications are back on.",
+		},
+		postNotification("guild-1", post),
+	)
+	stored, err = h.Store.GetMonitoredUser("guild-1", creatorID)
+	if err != nil || stored == nil || !stored.PostsEnabled || stored.PostsSuspended {
+		t.Errorf("subscription after regaining access = %+v, %v; want posts enabled", stored, err)
+	}
+}
+
+func TestFollowRegainsTimeline(t *testing.T) {
+	h := newScenario(t)
+	h.Monitor(t, subscription("guild-1"))
+
+	// The bot's account was unfollowed from a followers-only timeline.
+	h.Fansly.RequireFollow(creatorID)
+	post := api.Post{ID: "1001", Content: "Followers only", CreatedAt: 1700000000}
+	h.Fansly.Post(creatorID, post)
+
+	expectNotifications(t, h.RunCycle(), postNotification("guild-1", post))
+	if !h.Fansly.Followed(creatorID) {
+		t.Errorf("bot account doesn't follow the creator after losing access")
+	}
+}
+
+func TestFollowingFetchedOncePerCycle(t *testing.T) {
+	h := newScenario(t)
+	h.Fansly.AddCreator("200", "other", avatarURL)
+	other := subscription("guild-1")
+	other.UserID = "200"
+	other.Username = "other"
+	h.Monitor(t, subscription("guild-1"))
+	h.Monitor(t, other)
+
+	// Both creators need a follow in the same cycle.
+	h.Fansly.RequireFollow(creatorID)
+	h.Fansly.RequireFollow("200")
+	h.RunCycle()
+
+	if !h.Fansly.Followed(creatorID) || !h.Fansly.Followed("200") {
+		t.Errorf("bot account doesn't follow both creators after losing access")
+	}
+	if got := h.Fansly.FollowingRequests(); got != 1 {
+		t.Errorf("following list requested %d times in one cycle, want 1", got)
+	}
+}
+
+func TestRename(t *testing.T) {
+	h := newScenario(t)
+	announcing := subscription("guild-1")
+	announcing.AvatarLocationUpdatedAt = 1 // stale, so the profile is refreshed
+	h.Monitor(t, announcing)
+	silent := subscription("guild-2")
+	silent.AvatarLocationUpdatedAt = 1
+	h.Monitor(t, silent)
+
+	settings, err := h.Store.GetGuildSettings("guild-1")
+	if err != nil {
+		t.Fatalf("GetGuildSettings: %v", err)
+	}
+	settings.AnnounceRenames = true
+	if err := h.Store.SaveGuildSettings(settings); err != nil {
+		t.Fatalf("SaveGuildSettings: %v", err)
+	}
+
+	h.Fansly.Rename(creatorID, "renamed")
+	expectNotifications(t, h.RunCycle(), Notification{
+		ChannelID: "guild-1-posts",
+		Content:   "✏️ **" + creatorName + "** is now **renamed** on Fansly: <https://fansly.com/renamed>",
+	})
+
+	for _, guildID := range []string{"guild-1", "guild-2"} {
+		stored, err := h.Store.GetMonitoredUser(guildID, creatorID)
+		if err != nil || stored == nil || stored.Username != "renamed" {
+			t.Errorf("subscription in %s after the rename = %+v, %v; want username renamed", guildID, stored, err)
+		}
+	}
+	alias, err := h.Store.GetCreatorAlias(creatorName)
+	if err != nil || alias == nil || alias.UserID != creatorID {
+		t.Errorf("GetCreatorAlias(%s) = %+v, %v; want an alias of %s", creatorName, alias, err, creatorID)
+	}
+}
+
+func TestRenameWithoutAvatar(t *testing.T) {
+	h := NewHarness(t)
+	h.Fansly.AddCreator(creatorID, creatorName, "")
+	user := subscription("guild-1")
+	user.AvatarLocationUpdatedAt = 1
+	h.Monitor(t, user)
+
+	h.Fansly.Rename(creatorID, "renamed")
+	h.RunCycle()
+
+	// The stored avatar is kept, and stamped so it isn't fetched every cycle.
+	stored, err := h.Store.GetMonitoredUser("guild-1", creatorID)
+	if err != nil || stored == nil || stored.Username != "renamed" ||
+		stored.AvatarLocation != avatarURL || stored.AvatarLocationUpdatedAt != h.Clock.Now().Unix() {
+		t.Errorf("subscription after the rename = %+v, %v; want username renamed and the avatar kept", stored, err)
+	}
+	alias, err := h.Store.GetCreatorAlias(creatorName)
+	if err != nil || alias == nil || alias.UserID != creatorID {
+		t.Errorf("GetCreatorAlias(%s) = %+v, %v; want an alias of %s", creatorName, alias, err, creatorID)
+	}
+}
+
+func TestReconcileFollows(t *testing.T) {
+	grace := config.UnfollowGraceHours
+	config.UnfollowGraceHours = 24
+	t.Cleanup(func() { config.UnfollowGraceHours = grace })
+
+	h := newScenario(t)
+	h.Monitor(t, subscription("guild-1"))
+	// Followed by hand and never monitored, so reconciliation leaves it alone.
+	h.Fansly.AddCreator("200", "personal", "")
+	h.Fansly.Follow("200")
+	h.RunCycle()
+
+	report, err := h.Bot.ReconcileFollows(true)
+	if err != nil {
+		t.Fatalf("ReconcileFollows(dry run): %v", err)
+	}
+	if !slices.Equal(report.Missing, []string{creatorName}) || len(report.Followed) != 0 || h.Fansly.Followed(creatorID) {
+		t.Errorf("dry run: missing %v, followed %v, creator followed %t; want only %s reported missing",
+			report.Missing, report.Followed, h.Fansly.Followed(creatorID), creatorName)
+	}
+
+	if report, err = h.Bot.ReconcileFollows(false); err != nil {
+		t.Fatalf("ReconcileFollows: %v", err)
+	}
+	if !slices.Equal(report.Followed, []string{creatorName}) || !h.Fansly.Followed(creatorID) {
+		t.Errorf("followed %v, creator followed %t; want %s followed", report.Followed, h.Fansly.Followed(creatorID), creatorName)
+	}
+
+	if err := h.Store.DeleteMonitoredUser("guild-1", creatorID); err != nil {
+		t.Fatalf("DeleteMonitoredUser: %v", err)
+	}
+	h.RunCycle()
+
+	if report, err = h.Bot.ReconcileFollows(false); err != nil {
+		t.Fatalf("ReconcileFollows: %v", err)
+	}
+	if len(report.Pending) != 1 || len(report.Unfollowed) != 0 || !h.Fansly.Followed(creatorID) {
+		t.Errorf("within the grace period: pending %v, unfollowed %v; want the creator pending and still followed",
+			report.Pending, report.Unfollowed)
+	}
+
+	h.Clock.Advance(25 * time.Hour)
+	if report, err = h.Bot.ReconcileFollows(false); err != nil {
+		t.Fatalf("ReconcileFollows: %v", err)
+	}
+	if !slices.Equal(report.Unfollowed, []string{creatorName}) || h.Fansly.Followed(creatorID) {
+		t.Errorf("after the grace period: unfollowed %v, creator followed %t; want %s unfollowed",
+			report.Unfollowed, h.Fansly.Followed(creatorID), creatorName)
+	}
+	if !h.Fansly.Followed("200") {
+		t.Errorf("a creator that was never monitored was unfollowed")
+	}
+	if marks, err := h.Store.GetUnmonitoredCreators(); err != nil || len(marks) != 0 {
+		t.Errorf("unmonitored creators after unfollowing = %v, %v; want none", marks, err)
+	}
+}
@@ -0,0 +1,340 @@
+package bot
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fvckgrimm/discord-fansly-notify/api"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/config"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+const (
+	maxBulkAddCreators = 100
+	maxBulkAddFileSize = 64 << 10
+	// maxInlineResultLength keeps the result table within a message; longer
+	// tables are attached as a file.
+	maxInlineResultLength = 1900
+	maxLogCreatorsLength  = 1500
+)
+
+// Outcomes of adding a single creator with /bulkadd.
+const (
+	bulkAdded            = "added"
+	bulkLiveOnly         = "live only (no timeline access)"
+	bulkAlreadyMonitored = "already monitored"
+	bulkNotFound         = "not found"
+	bulkOverLimit        = "over limit"
+	bulkInvalid          = "invalid username"
+	bulkFailed           = "error"
+)
+
+var attachmentClient = &http.Client{Timeout: 30 * time.Second}
+
+type bulkAddResult struct {
+	Username string
+	Result   string
+}
+
+// parseCreatorList splits a list of usernames or Fansly URLs separated by new
+// lines, commas, semicolons or spaces.
+func parseCreatorList(text string) []string {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ';' || r == '\n' || r == '\r' || r == '\t' || r == ' '
+	})
+
+	usernames := make([]string, 0, len(fields))
+	for _, field := range fields {
+		username := normalizeUsername(strings.Trim(field, `"'`))
+		if username == "" || strings.EqualFold(username, "username") {
+			continue
+		}
+		usernames = append(usernames, username)
+	}
+	return usernames
+}
+
+// parseCreatorCSV reads the first column of every row, skipping a header.
+func parseCreatorCSV(data []byte) ([]string, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var column []string
+	for _, record := range records {
+		if len(record) > 0 {
+			column = append(column, record[0])
+		}
+	}
+	return parseCreatorList(strings.Join(column, "\n")), nil
+}
+
+// readCreatorFile downloads an attached .txt or .csv list of creators.
+func readCreatorFile(attachment *discordgo.MessageAttachment) ([]string, error) {
+	ext := strings.ToLower(path.Ext(attachment.Filename))
+	if ext != ".txt" && ext != ".csv" {
+		return nil, fmt.Errorf("only .txt and .csv files are supported")
+	}
+	if attachment.Size > maxBulkAddFileSize {
+		return nil, fmt.Errorf("the file is larger than %d KB", maxBulkAddFileSize>>10)
+	}
+
+	resp, err := attachmentClient.Get(attachment.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download the file: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download the file: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBulkAddFileSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download the file: %w", err)
+	}
+
+	if ext == ".csv" {
+		return parseCreatorCSV(data)
+	}
+	return parseCreatorList(string(data)), nil
+}
+
+// uniqueUsernames drops repeated usernames, ignoring case.
+func uniqueUsernames(usernames []string) []string {
+	seen := make(map[string]bool, len(usernames))
+	unique := make([]string, 0, len(usernames))
+	for _, username := range usernames {
+		key := strings.ToLower(username)
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, username)
+		}
+	}
+	return unique
+}
+
+func (b *Bot) handleBulkAddCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	usernames := parseCreatorList(opts.String("usernames"))
+	if opts.Has("file") {
+		attachment := i.ApplicationCommandData().Resolved.Attachments[opts.String("file")]
+		if attachment == nil {
+			b.editInteractionResponse(s, i, "Error: the attached file could not be read.")
+			return
+		}
+		fromFile, err := readCreatorFile(attachment)
+		if err != nil {
+			b.editInteractionResponse(s, i, fmt.Sprintf("Error: %v", err))
+			return
+		}
+		usernames = append(usernames, fromFile...)
+	}
+
+	usernames = uniqueUsernames(usernames)
+	if len(usernames) == 0 {
+		b.editInteractionResponse(s, i, "Provide usernames or Fansly links, or attach a .txt or .csv file with one creator per line.")
+		return
+	}
+	if len(usernames) > maxBulkAddCreators {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error: at most %d creators can be added at once, got %d.", maxBulkAddCreators, len(usernames)))
+		return
+	}
+
+	var mentionRole string
+	if role := opts.Role(s, i.GuildID, "mention_role"); role != nil {
+		mentionRole = role.ID
+	}
+
+	channel := opts.Channel(s, "channel")
+	channelWarning, deliverable := b.validateNotificationChannel(channel, models.MonitoredUser{
+		GuildID: i.GuildID,
+	}, mentionRole)
+	if !deliverable {
+		b.editInteractionResponse(s, i, "Error: "+channelWarning)
+		return
+	}
+
+	b.editInteractionResponse(s, i, fmt.Sprintf("Looking up %d creators…", len(usernames)))
+
+	results := b.bulkAddCreators(i.GuildID, usernames, channel.ID, mentionRole)
+	b.logBulkAdd(s, i, results)
+
+	summary := summarizeBulkAdd(results)
+	if channelWarning != "" {
+		summary += "\n⚠️ " + channelWarning
+	}
+	b.sendBulkAddResults(s, i, summary, formatBulkAddTable(results))
+}
+
+// bulkAddCreators adds each creator like /add does, without asking for
+// confirmation: creators whose timeline can't be read are added for live
+// notifications only.
+func (b *Bot) bulkAddCreators(guildID string, usernames []string, channelID, mentionRole string) []bulkAddResult {
+	results := make([]bulkAddResult, 0, len(usernames))
+
+	var lookup []string
+	for _, username := range usernames {
+		if tokenRegex.MatchString(username) {
+			results = append(results, bulkAddResult{"(hidden)", bulkInvalid})
+			continue
+		}
+		lookup = append(lookup, username)
+	}
+
+	accounts, err := b.APIClient.GetAccountsInfo(lookup)
+	if err != nil {
+		log.Printf("Error looking up creators for bulk add in guild %s: %v", guildID, err)
+		for _, username := range lookup {
+			results = append(results, bulkAddResult{username, bulkFailed})
+		}
+		return results
+	}
+
+	byUsername := make(map[string]*api.ModelAccountInfo, len(accounts))
+	for idx := range accounts {
+		byUsername[strings.ToLower(accounts[idx].Username)] = &accounts[idx]
+	}
+
+	count, err := b.Repo.CountMonitoredUsersForGuild(guildID)
+	if err != nil {
+		log.Printf("Error checking guild limit for guild %s: %v", guildID, err)
+	}
+
+	follows := &followCache{}
+	for _, username := range lookup {
+		account, ok := byUsername[strings.ToLower(username)]
+		if !ok {
+			results = append(results, bulkAddResult{username, bulkNotFound})
+			continue
+		}
+		username = account.Username
+
+		if existing, err := b.Repo.GetMonitoredUser(guildID, account.ID); err == nil && existing != nil {
+			results = append(results, bulkAddResult{username, bulkAlreadyMonitored})
+			continue
+		}
+
+		if config.MaxMonitoredUsersPerGuild > 0 && count >= int64(config.MaxMonitoredUsersPerGuild) {
+			results = append(results, bulkAddResult{username, bulkOverLimit})
+			continue
+		}
+
+		postsEnabled := b.ensureTimelineAccess(account.ID, username, follows)
+		user := newMonitoredUser(guildID, account, username, channelID, mentionRole, postsEnabled)
+		if err := b.Repo.AddOrUpdateMonitoredUser(user); err != nil {
+			log.Printf("Error storing %s during bulk add in guild %s: %v", username, guildID, err)
+			results = append(results, bulkAddResult{username, bulkFailed})
+			continue
+		}
+		count++
+
+		if postsEnabled {
+			results = append(results, bulkAddResult{username, bulkAdded})
+		} else {
+			results = append(results, bulkAddResult{username, bulkLiveOnly})
+		}
+	}
+
+	return results
+}
+
+func summarizeBulkAdd(results []bulkAddResult) string {
+	counts := make(map[string]int)
+	for _, result := range results {
+		counts[result.Result]++
+	}
+
+	parts := []string{fmt.Sprintf("**%d** added", counts[bulkAdded])}
+	for _, outcome := range []string{bulkLiveOnly, bulkAlreadyMonitored, bulkNotFound, bulkOverLimit, bulkInvalid, bulkFailed} {
+		if counts[outcome] > 0 {
+			parts = append(parts, fmt.Sprintf("**%d** %s", counts[outcome], outcome))
+		}
+	}
+	return "Bulk add finished: " + strings.Join(parts, ", ") + "."
+}
+
+func formatBulkAddTable(results []bulkAddResult) string {
+	width := len("Creator")
+	for _, result := range results {
+		width = max(width, len(result.Username))
+	}
+
+	var table strings.Builder
+	fmt.Fprintf(&table, "%-*s  %s\n", width, "Creator", "Result")
+	for _, result := range results {
+		fmt.Fprintf(&table, "%-*s  %s\n", width, result.Username, result.Result)
+	}
+	return table.String()
+}
+
+// sendBulkAddResults shows the table in a code block, or as an attached file
+// when it doesn't fit in a message.
+func (b *Bot) sendBulkAddResults(s *discordgo.Session, i *discordgo.InteractionCreate, summary, table string) {
+	if len(summary)+len(table) < maxInlineResultLength {
+		b.editInteractionResponse(s, i, fmt.Sprintf("%s\n```\n%s```", summary, table))
+		return
+	}
+
+	_, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: &summary,
+		Files: []*discordgo.File{{
+			Name:        "bulkadd-results.txt",
+			ContentType: "text/plain",
+			Reader:      strings.NewReader(table),
+		}},
+	})
+	if err != nil {
+		log.Printf("Error editing interaction response: %v", err)
+	}
+}
+
+// logBulkAdd reports the creators added to the bot's log channel, like /add.
+func (b *Bot) logBulkAdd(s *discordgo.Session, i *discordgo.InteractionCreate, results []bulkAddResult) {
+	if config.LogChannelID == "" {
+		return
+	}
+
+	var added []string
+	for _, result := range results {
+		if result.Result == bulkAdded || result.Result == bulkLiveOnly {
+			added = append(added, "`"+result.Username+"`")
+		}
+	}
+	if len(added) == 0 {
+		return
+	}
+
+	creators := strings.Join(added, ", ")
+	if len(creators) > maxLogCreatorsLength {
+		creators = creators[:maxLogCreatorsLength] + "…"
+	}
+
+	guildName := "Unknown Server"
+	if guild, err := s.State.Guild(i.GuildID); err == nil {
+		guildName = guild.Name
+	}
+
+	logMessage := fmt.Sprintf(
+		"`[%s]` User <@%s> (`%s`) bulk added %d creators:\n**Creators:** %s\n**Server:** %s (`%s`)",
+		time.Now().Format("2006-01-02 15:04:05"),
+		interactionUserID(i),
+		i.Member.User.Username,
+		len(added),
+		creators,
+		guildName,
+		i.GuildID,
+	)
+	if _, err := s.ChannelMessageSend(config.LogChannelID, logMessage); err != nil {
+		log.Printf("Failed to send log message to channel %s: %v", config.LogChannelID, err)
+	}
+}
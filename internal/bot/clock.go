@@ -0,0 +1,21 @@
+package bot
+
+import "time"
+
+// Clock provides the current time. Scheduling code reads time through it so
+// quiet hours, mutes and digests can be exercised with a fixed clock.
+type Clock interface {
+	Now() time.Time
+	// AfterFunc calls f once d has passed on the clock.
+	AfterFunc(d time.Duration, f func())
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) {
+	time.AfterFunc(d, f)
+}
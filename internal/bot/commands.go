@@ -1,210 +1,880 @@
 package bot
 
 import (
-	"github.com/bwmarrin/discordgo"
 	"log"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
 )
 
-func (b *Bot) registerCommands() {
-	commands := []*discordgo.ApplicationCommand{
-		{
-			Name:        "add",
-			Description: "Add a Fansly model to monitor",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "username",
-					Description: "Fansly username",
-					Required:    true,
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionChannel,
-					Name:        "channel",
-					Description: "Notification channel",
-					Required:    true,
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionRole,
-					Name:        "mention_role",
-					Description: "Role to mention (optional)",
-					Required:    false,
-				},
+// newCommands declares every slash command: its options, who may run it, how
+// it is acknowledged and which handler runs it.
+func (b *Bot) newCommands() *commandRegistry {
+	minDigestHour := 0.0
+
+	r := newCommandRegistry()
+	r.use(b.recoverMiddleware, b.logMiddleware, b.authorizeMiddleware, b.deferMiddleware, b.creatorMiddleware)
+
+	r.group("creator", "Manage the creators monitored in this server")
+	r.group("notify", "Configure how a creator's notifications are sent")
+	r.group("settings", "Configure server-wide behaviour")
+	r.group("owner", "[Owner Only] Manage the bot's servers")
+
+	r.add(&command{
+		Path:        "creator add",
+		Description: "Add a Fansly model to monitor",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "username",
+				Description: "Fansly username",
+				Required:    true,
+			},
+			{
+				Type:         discordgo.ApplicationCommandOptionChannel,
+				Name:         "channel",
+				Description:  "Notification channel",
+				Required:     true,
+				ChannelTypes: notificationChannelTypes,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionRole,
+				Name:        "mention_role",
+				Description: "Role to mention (optional)",
+				Required:    false,
 			},
 		},
-		{
-			Name:        "remove",
-			Description: "Remove a Fansly model from monitoring",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "username",
-					Description: "Fansly username",
-					Required:    true,
-				},
+		Access:     models.ScopeNotifications,
+		Defer:      deferPublic,
+		NewCreator: true,
+		Handler:    b.handleAddCommand,
+		Aliases:    []string{"add"},
+	})
+	r.add(&command{
+		Path:        "creator bulkadd",
+		Description: "Add many Fansly models at once from a list or a .txt/.csv file",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:         discordgo.ApplicationCommandOptionChannel,
+				Name:         "channel",
+				Description:  "Notification channel",
+				Required:     true,
+				ChannelTypes: notificationChannelTypes,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "usernames",
+				Description: "Usernames or Fansly links separated by commas or spaces",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionAttachment,
+				Name:        "file",
+				Description: "A .txt or .csv file with one creator per line",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionRole,
+				Name:        "mention_role",
+				Description: "Role to mention (optional)",
+				Required:    false,
 			},
 		},
-		{
-			Name:        "list",
-			Description: "List all monitored models",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Type:        discordgo.ApplicationCommandOptionInteger,
-					Name:        "page",
-					Description: "Page number to display",
-					Required:    false,
-				},
+		Access:  models.ScopeNotifications,
+		Defer:   deferPublic,
+		Handler: b.handleBulkAddCommand,
+		Aliases: []string{"bulkadd"},
+	})
+	r.add(&command{
+		Path:        "creator remove",
+		Description: "Remove a Fansly model from monitoring",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "username",
+				Description: "Fansly username",
+				Required:    true,
 			},
 		},
-		{
-			Name:        "setliveimage",
-			Description: "Set a custom live image for a model",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "username",
-					Description: "The username of the model",
-					Required:    true,
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionAttachment,
-					Name:        "image",
-					Description: "The image to use for live notifications",
-					Required:    true,
-				},
+		Access:  models.ScopeNotifications,
+		Defer:   deferPublic,
+		Handler: b.handleRemoveCommand,
+		Aliases: []string{"remove"},
+	})
+	r.add(&command{
+		Path:        "creator list",
+		Description: "List all monitored models",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "page",
+				Description: "Page number to display",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "filter",
+				Description: "Only show some creators",
+				Required:    false,
+				Choices:     filterChoices(listCreators),
 			},
 		},
-		{
-			Name:        "toggle",
-			Description: "Toggle notifications for a model",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "username",
-					Description: "Fansly username",
-					Required:    true,
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "type",
-					Description: "Notification type to toggle",
-					Required:    true,
-					Choices: []*discordgo.ApplicationCommandOptionChoice{
-						{
-							Name:  "Posts",
-							Value: "posts",
-						},
-						{
-							Name:  "Live",
-							Value: "live",
-						},
+		Access:  models.ScopeRead,
+		Defer:   deferPublic,
+		Handler: b.handleListCommand,
+		Aliases: []string{"list"},
+	})
+	r.add(&command{
+		Path:        "status",
+		Description: "Show whether monitoring and delivery work for each model",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "username",
+				Description: "Only show this model",
+				Required:    false,
+			},
+		},
+		Access:  models.ScopeRead,
+		Defer:   deferEphemeral,
+		Handler: b.handleStatusCommand,
+	})
+	r.add(&command{
+		Path:        "test",
+		Description: "Send a sample notification to check the bot can post in the channel",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "username",
+				Description: "Fansly username",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "type",
+				Description: "Notification to send (defaults to post)",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{
+						Name:  "Post",
+						Value: "post",
+					},
+					{
+						Name:  "Live",
+						Value: "live",
 					},
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionBoolean,
-					Name:        "enabled",
-					Description: "Enable or disable notifications",
-					Required:    true,
 				},
 			},
 		},
-		{
-			Name:        "setchannel",
-			Description: "Set notification channel for posts or live notifications",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "username",
-					Description: "Fansly username",
-					Required:    true,
-				},
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "type",
-					Description: "notification type",
-					Required:    true,
-					Choices: []*discordgo.ApplicationCommandOptionChoice{
-						{
-							Name:  "Posts",
-							Value: "posts",
-						},
-						{
-							Name:  "Live",
-							Value: "live",
-						},
+		Access:  models.ScopeNotifications,
+		Defer:   deferEphemeral,
+		Handler: b.handleTestCommand,
+	})
+
+	r.add(&command{
+		Path:        "notify toggle",
+		Description: "Toggle notifications for a model",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "username",
+				Description: "Fansly username",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "type",
+				Description: "Notification type to toggle",
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{
+						Name:  "Posts",
+						Value: "posts",
+					},
+					{
+						Name:  "Live",
+						Value: "live",
 					},
 				},
-				{
-					Type:        discordgo.ApplicationCommandOptionChannel,
-					Name:        "channel",
-					Description: "The notification channel",
-					Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "enabled",
+				Description: "Enable or disable notifications",
+				Required:    true,
+			},
+		},
+		Access:  models.ScopeNotifications,
+		Defer:   deferPublic,
+		Handler: b.handleToggleCommand,
+		Aliases: []string{"toggle"},
+	})
+	r.add(&command{
+		Path:        "notify channel",
+		Description: "Set notification channel for posts or live notifications",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "username",
+				Description: "Fansly username",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "type",
+				Description: "notification type",
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{
+						Name:  "Posts",
+						Value: "posts",
+					},
+					{
+						Name:  "Live",
+						Value: "live",
+					},
 				},
 			},
+			{
+				Type:         discordgo.ApplicationCommandOptionChannel,
+				Name:         "channel",
+				Description:  "The notification channel",
+				Required:     true,
+				ChannelTypes: notificationChannelTypes,
+			},
 		},
-		{
-			Name:        "setpostmention",
-			Description: "Set role to mention for post notifications",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "username",
-					Description: "Fansly username",
-					Required:    true,
+		Access:  models.ScopeNotifications,
+		Defer:   deferPublic,
+		Handler: b.handleSetChannelCommand,
+		Aliases: []string{"setchannel"},
+	})
+	r.add(&command{
+		Path:        "notify mention",
+		Description: "Set or clear the role mentioned for post or live notifications",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "username",
+				Description: "Fansly username",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "type",
+				Description: "Notification type",
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{
+						Name:  "Posts",
+						Value: "posts",
+					},
+					{
+						Name:  "Live",
+						Value: "live",
+					},
 				},
-				{
-					Type:        discordgo.ApplicationCommandOptionRole,
-					Name:        "role",
-					Description: "Role to mention (optional)",
-					Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionRole,
+				Name:        "role",
+				Description: "Role to mention; leave empty to clear",
+				Required:    false,
+			},
+		},
+		Access:  models.ScopeNotifications,
+		Defer:   deferPublic,
+		Handler: b.handleNotifyMentionCommand,
+	})
+
+	r.add(&command{
+		Path:        "setliveimage",
+		Description: "Set a custom live image for a model",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "username",
+				Description: "The username of the model",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionAttachment,
+				Name:        "image",
+				Description: "The image to use for live notifications",
+				Required:    true,
+			},
+		},
+		Access:  models.ScopeNotifications,
+		Defer:   deferPublic,
+		Handler: b.handleSetLiveImageCommand,
+	})
+	r.add(&command{
+		Path:        "setpostmention",
+		Description: "Set role to mention for post notifications",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "username",
+				Description: "Fansly username",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionRole,
+				Name:        "role",
+				Description: "Role to mention (optional)",
+				Required:    false,
+			},
+		},
+		Access:  models.ScopeNotifications,
+		Defer:   deferPublic,
+		Handler: b.handleSetPostMentionCommand,
+	})
+	r.add(&command{
+		Path:        "setlivemention",
+		Description: "Set role to mention for live notifications",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "username",
+				Description: "Fansly username",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionRole,
+				Name:        "role",
+				Description: "Role to mention (optional)",
+				Required:    false,
+			},
+		},
+		Access:  models.ScopeNotifications,
+		Defer:   deferPublic,
+		Handler: b.handleSetLiveMentionCommand,
+	})
+	r.add(&command{
+		Path:        "notify delivery",
+		Description: "Choose whether post notifications are sent instantly or as a digest",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "username",
+				Description: "Fansly username",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "mode",
+				Description: "Delivery mode for post notifications",
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{
+						Name:  "Instant",
+						Value: "instant",
+					},
+					{
+						Name:  "Hourly digest",
+						Value: "hourly",
+					},
+					{
+						Name:  "Daily digest",
+						Value: "daily",
+					},
 				},
 			},
 		},
-		{
-			Name:        "setlivemention",
-			Description: "Set role to mention for live notifications",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "username",
-					Description: "Fansly username",
-					Required:    true,
+		Access:  models.ScopeNotifications,
+		Defer:   deferPublic,
+		Handler: b.handleSetDeliveryCommand,
+	})
+	r.add(&command{
+		Path:        "settings timezone",
+		Description: "Set the server timezone used for digests and quiet hours",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "timezone",
+				Description: "IANA timezone name, e.g. Europe/Berlin",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "digest_hour",
+				Description: "Hour of day (0-23) to send daily digests",
+				Required:    false,
+				MinValue:    &minDigestHour,
+				MaxValue:    23,
+			},
+		},
+		Access:  models.ScopeSettings,
+		Defer:   deferPublic,
+		Handler: b.handleSetTimezoneCommand,
+	})
+	r.add(&command{
+		Path:        "notify identity",
+		Description: "Choose whether notifications are posted as the bot or as the creator",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "username",
+				Description: "Fansly username",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "identity",
+				Description: "Who the notifications should appear to come from",
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{
+						Name:  "Bot",
+						Value: "bot",
+					},
+					{
+						Name:  "Creator (webhook)",
+						Value: "creator",
+					},
 				},
-				{
-					Type:        discordgo.ApplicationCommandOptionRole,
-					Name:        "role",
-					Description: "Role to mention (optional)",
-					Required:    false,
+			},
+		},
+		Access:  models.ScopeNotifications,
+		Defer:   deferPublic,
+		Handler: b.handleSetIdentityCommand,
+	})
+	r.add(&command{
+		Path:        "notify threads",
+		Description: "Start a discussion thread on each notification",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "username",
+				Description: "Fansly username",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "type",
+				Description: "Notification type",
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{
+						Name:  "Posts",
+						Value: "posts",
+					},
+					{
+						Name:  "Live",
+						Value: "live",
+					},
 				},
 			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "enabled",
+				Description: "Enable or disable threads",
+				Required:    true,
+			},
+		},
+		Access:  models.ScopeNotifications,
+		Defer:   deferPublic,
+		Handler: b.handleSetThreadsCommand,
+	})
+	r.add(&command{
+		Path:        "notify crosspost",
+		Description: "Automatically publish notifications sent to announcement channels",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "username",
+				Description: "Fansly username",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "enabled",
+				Description: "Enable or disable auto-publishing",
+				Required:    true,
+			},
+		},
+		Access:  models.ScopeNotifications,
+		Defer:   deferPublic,
+		Handler: b.handleSetCrosspostCommand,
+	})
+	r.add(&command{
+		Path:        "notify buttons",
+		Description: "Add a self-assignable \"notify me\" button to notifications",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "username",
+				Description: "Fansly username",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "enabled",
+				Description: "Enable or disable the buttons",
+				Required:    true,
+			},
+		},
+		Access:  models.ScopeNotifications,
+		Defer:   deferPublic,
+		Handler: b.handleSetButtonsCommand,
+	})
+	r.add(&command{
+		Path:        "subscribe",
+		Description: "Get a DM when a creator monitored in this server posts or goes live",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "username",
+				Description: "Fansly username",
+				Required:    true,
+			},
 		},
-		// --- NEW BOT OWNER COMMANDS ---
-		{
-			Name:        "servers",
-			Description: "[Owner Only] List all servers the bot is in.",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Type:        discordgo.ApplicationCommandOptionInteger,
-					Name:        "page",
-					Description: "Page number to display",
-					Required:    false,
+		Access:  accessMember,
+		Defer:   deferEphemeral,
+		Handler: b.handleSubscribeCommand,
+	})
+	r.add(&command{
+		Path:        "unsubscribe",
+		Description: "Stop getting DMs for a creator",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "username",
+				Description: "Fansly username",
+				Required:    true,
+			},
+		},
+		Access:  accessMember,
+		Defer:   deferEphemeral,
+		Handler: b.handleUnsubscribeCommand,
+	})
+	r.add(&command{
+		Path:        "mysubscriptions",
+		Description: "List the creators you get DMs for",
+		Access:      accessMember,
+		Defer:       deferEphemeral,
+		Handler:     b.handleMySubscriptionsCommand,
+		AllowDM:     true,
+	})
+	r.add(&command{
+		Path:        "export",
+		Description: "Export this server's creators, settings and permissions as a JSON file",
+		Access:      models.ScopeSettings,
+		Defer:       deferEphemeral,
+		Handler:     b.handleExportCommand,
+	})
+	r.add(&command{
+		Path:        "import",
+		Description: "Import creators, settings and permissions from an /export file",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionAttachment,
+				Name:        "file",
+				Description: "The .json file created by /export",
+				Required:    true,
+			},
+			{
+				Type:         discordgo.ApplicationCommandOptionChannel,
+				Name:         "channel",
+				Description:  "Channel to use for channels that no longer exist (defaults to this one)",
+				Required:     false,
+				ChannelTypes: notificationChannelTypes,
+			},
+		},
+		Access:  accessAdmin,
+		Defer:   deferEphemeral,
+		Handler: b.handleImportCommand,
+	})
+	r.add(&command{
+		Path:        "permissions grant",
+		Description: "Grant a role or member a permission scope",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionMentionable,
+				Name:        "target",
+				Description: "Role or member",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "scope",
+				Description: "What they may do; each scope includes the ones above it",
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{
+						Name:  "Read (view the monitored list)",
+						Value: models.ScopeRead,
+					},
+					{
+						Name:  "Notifications (add, remove and configure creators)",
+						Value: models.ScopeNotifications,
+					},
+					{
+						Name:  "Settings (timezone, quiet hours, webhooks, events)",
+						Value: models.ScopeSettings,
+					},
 				},
 			},
 		},
-		{
-			Name:        "leave",
-			Description: "[Owner Only] Make the bot leave a specific server.",
-			Options: []*discordgo.ApplicationCommandOption{
-				{
-					Type:        discordgo.ApplicationCommandOptionString,
-					Name:        "server",
-					Description: "The ID or Name of the server to leave.",
-					Required:    true,
+		Access:  accessAdmin,
+		Defer:   deferPublic,
+		Handler: b.handlePermissionsGrant,
+	})
+	r.add(&command{
+		Path:        "permissions revoke",
+		Description: "Remove a role's or member's grant",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionMentionable,
+				Name:        "target",
+				Description: "Role or member",
+				Required:    true,
+			},
+		},
+		Access:  accessAdmin,
+		Defer:   deferPublic,
+		Handler: b.handlePermissionsRevoke,
+	})
+	r.add(&command{
+		Path:        "permissions list",
+		Description: "List the grants in this server",
+		Access:      accessAdmin,
+		Defer:       deferPublic,
+		Handler:     b.handlePermissionsList,
+	})
+	r.add(&command{
+		Path:        "settings quiethours",
+		Description: "Configure quiet hours for this server",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "enabled",
+				Description: "Enable or disable quiet hours",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "start",
+				Description: "Start time in server timezone (HH:MM)",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "end",
+				Description: "End time in server timezone (HH:MM)",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "mode",
+				Description: "What happens to notifications during quiet hours",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{
+						Name:  "Send without role mentions",
+						Value: "suppress",
+					},
+					{
+						Name:  "Queue until quiet hours end",
+						Value: "queue",
+					},
 				},
 			},
 		},
-	}
+		Access:  models.ScopeSettings,
+		Defer:   deferPublic,
+		Handler: b.handleQuietHoursCommand,
+	})
+	r.add(&command{
+		Path:        "notify mute",
+		Description: "Temporarily mute notifications for a model",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "username",
+				Description: "Fansly username",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "duration",
+				Description: "How long to mute, e.g. 30m, 8h, 2d",
+				Required:    true,
+			},
+		},
+		Access:  models.ScopeNotifications,
+		Defer:   deferPublic,
+		Handler: b.handleMuteCommand,
+	})
+	r.add(&command{
+		Path:        "notify unmute",
+		Description: "Unmute notifications for a model",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "username",
+				Description: "Fansly username",
+				Required:    true,
+			},
+		},
+		Access:  models.ScopeNotifications,
+		Defer:   deferPublic,
+		Handler: b.handleUnmuteCommand,
+	})
+	r.add(&command{
+		Path:        "settings liveevents",
+		Description: "Mirror live streams as server events",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "enabled",
+				Description: "Create a server event while a creator is live",
+				Required:    true,
+			},
+		},
+		Access:  models.ScopeSettings,
+		Defer:   deferPublic,
+		Handler: b.handleLiveEventsCommand,
+	})
+	r.add(&command{
+		Path:        "settings announcerenames",
+		Description: "Announce when a monitored creator changes their username",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "enabled",
+				Description: "Post a message in the creator's post channel after a rename",
+				Required:    true,
+			},
+		},
+		Access:  models.ScopeSettings,
+		Defer:   deferPublic,
+		Handler: b.handleAnnounceRenamesCommand,
+	})
+	// Webhook responses may contain endpoint secrets, so they stay private.
+	r.add(&command{
+		Path:        "webhook add",
+		Description: "Register an endpoint that receives signed JSON events",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "url",
+				Description: "HTTPS URL to POST events to",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "global",
+				Description: "[Owner Only] Receive events from every server",
+				Required:    false,
+			},
+		},
+		Access:  models.ScopeSettings,
+		Defer:   deferEphemeral,
+		Handler: b.handleWebhookAdd,
+	})
+	r.add(&command{
+		Path:        "webhook remove",
+		Description: "Remove a registered endpoint",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "id",
+				Description: "Endpoint ID from /webhook list",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "global",
+				Description: "[Owner Only] The endpoint is a global one",
+				Required:    false,
+			},
+		},
+		Access:  models.ScopeSettings,
+		Defer:   deferEphemeral,
+		Handler: b.handleWebhookRemove,
+	})
+	r.add(&command{
+		Path:        "webhook test",
+		Description: "Send a test event to an endpoint",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "id",
+				Description: "Endpoint ID from /webhook list",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "global",
+				Description: "[Owner Only] The endpoint is a global one",
+				Required:    false,
+			},
+		},
+		Access:  models.ScopeSettings,
+		Defer:   deferEphemeral,
+		Handler: b.handleWebhookTest,
+	})
+	r.add(&command{
+		Path:        "webhook list",
+		Description: "List registered endpoints",
+		Access:      models.ScopeSettings,
+		Defer:       deferEphemeral,
+		Handler:     b.handleWebhookList,
+	})
 
-	_, err := b.Session.ApplicationCommandBulkOverwrite(b.Session.State.User.ID, "", commands)
+	r.add(&command{
+		Path:        "owner servers",
+		Description: "[Owner Only] List all servers the bot is in.",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "page",
+				Description: "Page number to display",
+				Required:    false,
+			},
+		},
+		Access:  accessOwner,
+		Defer:   deferPublic,
+		Handler: b.handleServersCommand,
+		Aliases: []string{"servers"},
+	})
+	r.add(&command{
+		Path:        "owner leave",
+		Description: "[Owner Only] Make the bot leave a specific server.",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "server",
+				Description: "The ID or Name of the server to leave.",
+				Required:    true,
+			},
+		},
+		Access:  accessOwner,
+		Defer:   deferPublic,
+		Handler: b.handleLeaveCommand,
+		Aliases: []string{"leave"},
+	})
+	r.add(&command{
+		Path:        "owner follows",
+		Description: "[Owner Only] Sync the bot account's Fansly follows with the monitored creators.",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "dry_run",
+				Description: "Only show what would change",
+				Required:    false,
+			},
+		},
+		Access:  accessOwner,
+		Defer:   deferPublic,
+		Handler: b.handleFollowsCommand,
+	})
+
+	return r
+}
+
+func (b *Bot) registerCommands() {
+	_, err := b.Session.ApplicationCommandBulkOverwrite(b.Session.State.User.ID, "", b.commands.definitions())
 	if err != nil {
 		log.Printf("Error registering commands: %v", err)
 	}
@@ -0,0 +1,210 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+// notifyMePrefix starts the custom ID of "notify me" buttons. The creator's
+// Fansly user ID follows it, so the button keeps working after restarts.
+const notifyMePrefix = "notifyme:"
+
+// handleComponentInteraction routes button clicks and select menus by custom
+// ID. Everything a component needs is in its custom ID, so components keep
+// working after restarts.
+func (b *Bot) handleComponentInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	customID := i.MessageComponentData().CustomID
+
+	switch {
+	case strings.HasPrefix(customID, notifyMePrefix):
+		b.handleNotifyMeButton(s, i, strings.TrimPrefix(customID, notifyMePrefix))
+	case strings.HasPrefix(customID, pagePrefix):
+		b.handlePageButton(s, i, strings.TrimPrefix(customID, pagePrefix))
+	case strings.HasPrefix(customID, pageJumpPrefix):
+		b.handlePageJumpButton(s, i, strings.TrimPrefix(customID, pageJumpPrefix))
+	case strings.HasPrefix(customID, pageFilterPrefix):
+		b.handlePageFilter(s, i, strings.TrimPrefix(customID, pageFilterPrefix))
+	case strings.HasPrefix(customID, importPrefix):
+		b.handleImportButton(s, i, strings.TrimPrefix(customID, importPrefix))
+	}
+}
+
+// handleModalSubmit routes submitted modals by custom ID.
+func (b *Bot) handleModalSubmit(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	customID := i.ModalSubmitData().CustomID
+
+	switch {
+	case strings.HasPrefix(customID, pageJumpPrefix):
+		b.handlePageJumpModal(s, i, strings.TrimPrefix(customID, pageJumpPrefix))
+	}
+}
+
+// notificationComponents returns the buttons attached to a notification when
+// the subscription has them enabled.
+func notificationComponents(n notification) []discordgo.MessageComponent {
+	if !n.User.NotifyButtons {
+		return nil
+	}
+
+	buttons := []discordgo.MessageComponent{
+		discordgo.Button{
+			Label:    truncateLabel(fmt.Sprintf("Notify me for %s", n.User.Username)),
+			Style:    discordgo.SecondaryButton,
+			CustomID: notifyMePrefix + n.User.UserID,
+			Emoji:    &discordgo.ComponentEmoji{Name: "🔔"},
+		},
+	}
+
+	if n.Embed != nil && n.Embed.URL != "" {
+		label := "View post"
+		switch {
+		case n.Kind == kindLive:
+			label = "Watch stream"
+		case n.Kind == kindDigest, n.Embed.Author != nil && n.Embed.URL == n.Embed.Author.URL:
+			label = "View profile"
+		}
+		buttons = append(buttons, discordgo.Button{
+			Label: label,
+			Style: discordgo.LinkButton,
+			URL:   n.Embed.URL,
+		})
+	}
+
+	return []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}}
+}
+
+// truncateLabel keeps button labels within Discord's 80 character limit.
+func truncateLabel(label string) string {
+	if runes := []rune(label); len(runes) > 80 {
+		return string(runes[:79]) + "…"
+	}
+	return label
+}
+
+// notifyRoleMention returns the mention for the creator's self-assignable role.
+func notifyRoleMention(user models.MonitoredUser) string {
+	if !user.NotifyButtons || user.NotifyRoleID == "" {
+		return ""
+	}
+	return fmt.Sprintf("<@&%s>", user.NotifyRoleID)
+}
+
+func joinMentions(mentions ...string) string {
+	var parts []string
+	for _, m := range mentions {
+		if m != "" {
+			parts = append(parts, m)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// ensureNotifyRole returns the creator's managed role, creating it when the
+// subscription does not have one yet or when recreate is set because the
+// stored role was deleted.
+func (b *Bot) ensureNotifyRole(user *models.MonitoredUser, recreate bool) (string, error) {
+	if user.NotifyRoleID != "" && !recreate {
+		return user.NotifyRoleID, nil
+	}
+
+	mentionable := true
+	role, err := b.Discord.GuildRoleCreate(user.GuildID, &discordgo.RoleParams{
+		Name:        fmt.Sprintf("🔔 %s", user.Username),
+		Mentionable: &mentionable,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := b.Repo.UpdateNotifyRoleID(user.GuildID, user.UserID, role.ID); err != nil {
+		// Don't leave an untracked role behind.
+		if delErr := b.Discord.GuildRoleDelete(user.GuildID, role.ID); delErr != nil {
+			log.Printf("Error deleting untracked notify role %s in guild %s: %v", role.ID, user.GuildID, delErr)
+		}
+		return "", err
+	}
+
+	user.NotifyRoleID = role.ID
+	return role.ID, nil
+}
+
+// deleteNotifyRole removes the creator's managed role from the guild.
+func (b *Bot) deleteNotifyRole(user *models.MonitoredUser) {
+	if user.NotifyRoleID == "" {
+		return
+	}
+
+	err := b.Discord.GuildRoleDelete(user.GuildID, user.NotifyRoleID)
+	if err != nil && discordErrorCode(err) != discordgo.ErrCodeUnknownRole {
+		log.Printf("Error deleting notify role %s for %s in guild %s: %v", user.NotifyRoleID, user.Username, user.GuildID, err)
+	}
+}
+
+func (b *Bot) handleNotifyMeButton(s *discordgo.Session, i *discordgo.InteractionCreate, creatorID string) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral},
+	})
+	if err != nil {
+		log.Printf("Error deferring interaction: %v", err)
+		return
+	}
+
+	if i.Member == nil {
+		b.editInteractionResponse(s, i, "This button only works inside a server.")
+		return
+	}
+
+	user, err := b.Repo.GetMonitoredUser(i.GuildID, creatorID)
+	if err != nil {
+		b.editInteractionResponse(s, i, "An error occurred. Please try again later.")
+		return
+	}
+	if user == nil || !user.NotifyButtons {
+		b.editInteractionResponse(s, i, "Notifications for this creator are no longer available here.")
+		return
+	}
+
+	roleID, err := b.ensureNotifyRole(user, false)
+	if err != nil {
+		log.Printf("Error creating notify role for %s in guild %s: %v", user.Username, i.GuildID, err)
+		b.editInteractionResponse(s, i, "I couldn't create the notification role. Ask an admin to give me the Manage Roles permission.")
+		return
+	}
+
+	hasRole := false
+	for _, r := range i.Member.Roles {
+		if r == roleID {
+			hasRole = true
+			break
+		}
+	}
+
+	if hasRole {
+		err = b.Discord.GuildMemberRoleRemove(i.GuildID, i.Member.User.ID, roleID)
+	} else {
+		err = b.Discord.GuildMemberRoleAdd(i.GuildID, i.Member.User.ID, roleID)
+		if discordErrorCode(err) == discordgo.ErrCodeUnknownRole {
+			// The role was deleted by hand; make a new one.
+			if roleID, err = b.ensureNotifyRole(user, true); err == nil {
+				err = b.Discord.GuildMemberRoleAdd(i.GuildID, i.Member.User.ID, roleID)
+			}
+		}
+	}
+
+	if err != nil {
+		log.Printf("Error toggling notify role for %s in guild %s: %v", user.Username, i.GuildID, err)
+		b.editInteractionResponse(s, i, "I couldn't update your roles. My role may need to be above the notification role.")
+		return
+	}
+
+	if hasRole {
+		b.editInteractionResponse(s, i, fmt.Sprintf("🔕 You will no longer be pinged for **%s**.", user.Username))
+		return
+	}
+	b.editInteractionResponse(s, i, fmt.Sprintf("🔔 You will be pinged when **%s** posts or goes live.", user.Username))
+}
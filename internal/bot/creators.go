@@ -0,0 +1,104 @@
+package bot
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/fvckgrimm/discord-fansly-notify/api"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+// followCache holds the accounts the bot's Fansly account follows. They are
+// fetched on first use, so following many creators in one go costs a single
+// fetch of the following list. It may be shared by concurrent workers.
+type followCache struct {
+	mu       sync.Mutex
+	loaded   bool
+	accounts map[string]bool // nil when the list couldn't be fetched
+}
+
+// followedAccounts returns the cached following list, fetching it first if
+// needed. The caller must hold cache.mu.
+func (b *Bot) followedAccounts(cache *followCache) map[string]bool {
+	if cache.loaded {
+		return cache.accounts
+	}
+	cache.loaded = true
+
+	myAccount, err := b.APIClient.GetMyAccountInfo()
+	if err != nil || myAccount.ID == "" {
+		return nil
+	}
+	following, err := b.APIClient.GetFollowing(myAccount.ID)
+	if err != nil {
+		return nil
+	}
+	cache.accounts = make(map[string]bool, len(following))
+	for _, f := range following {
+		cache.accounts[f.AccountID] = true
+	}
+	return cache.accounts
+}
+
+// ensureTimelineAccess reports whether the bot's Fansly account can read the
+// creator's timeline, following the creator first when it can't. cache may be
+// nil when only one creator is checked.
+func (b *Bot) ensureTimelineAccess(creatorID, username string, cache *followCache) bool {
+	if _, err := b.APIClient.GetTimelinePost(creatorID); err == nil {
+		return true
+	}
+
+	b.followCreator(creatorID, username, cache)
+
+	_, err := b.APIClient.GetTimelinePost(creatorID)
+	return err == nil
+}
+
+// followCreator follows the creator with the bot's Fansly account unless it
+// already does, which is enough to read timelines open to followers. cache
+// may be nil when only one creator is followed.
+func (b *Bot) followCreator(creatorID, username string, cache *followCache) {
+	if cache == nil {
+		cache = &followCache{}
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	followed := b.followedAccounts(cache)
+	if followed == nil || followed[creatorID] {
+		return
+	}
+	if followErr := b.APIClient.FollowAccount(creatorID); followErr != nil {
+		log.Printf("Note: Could not automatically follow %s: %v", username, followErr)
+		return
+	}
+	followed[creatorID] = true
+}
+
+// newMonitoredUser builds a new subscription sending every notification to one
+// channel. Post notifications are only enabled when the timeline is readable.
+func newMonitoredUser(guildID string, account *api.ModelAccountInfo, username, channelID, mentionRole string, postsEnabled bool) *models.MonitoredUser {
+	var avatarLocation string
+	if len(account.Avatar.Variants) > 0 && len(account.Avatar.Variants[0].Locations) > 0 {
+		avatarLocation = account.Avatar.Variants[0].Locations[0].Location
+	} else {
+		log.Printf("Warning: No avatar found for user %s", username)
+	}
+
+	return &models.MonitoredUser{
+		GuildID:                 guildID,
+		UserID:                  account.ID,
+		Username:                username,
+		NotificationChannel:     channelID,
+		PostNotificationChannel: channelID,
+		LiveNotificationChannel: channelID,
+		MentionRole:             mentionRole,
+		AvatarLocation:          avatarLocation,
+		AvatarLocationUpdatedAt: time.Now().Unix(),
+		PostsEnabled:            postsEnabled,
+		LiveEnabled:             true,
+		LiveMentionRole:         mentionRole,
+		PostMentionRole:         mentionRole,
+	}
+}
@@ -0,0 +1,144 @@
+package bot
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Discord allows 10 crossposts per announcement channel per hour. The limit is
+// tracked locally so a busy channel waits its turn instead of holding up the
+// queue for every other channel.
+const (
+	crosspostLimit       = 10
+	crosspostWindow      = time.Hour
+	crosspostQueueSize   = 256
+	crosspostMaxAttempts = 3
+)
+
+type crosspostJob struct {
+	GuildID   string
+	ChannelID string
+	MessageID string
+	Username  string
+	Attempts  int
+}
+
+// crossposter publishes announcement channel messages to following servers.
+// Jobs are handled by a single worker, separately from notification sending.
+type crossposter struct {
+	discord DiscordAPI
+	clock   Clock
+	jobs    chan crosspostJob
+
+	mu   sync.Mutex
+	sent map[string][]time.Time
+}
+
+func newCrossposter(discord DiscordAPI, clock Clock) *crossposter {
+	return &crossposter{
+		discord: discord,
+		clock:   clock,
+		jobs:    make(chan crosspostJob, crosspostQueueSize),
+		sent:    make(map[string][]time.Time),
+	}
+}
+
+// enqueue adds a job without blocking. When the queue is full the message is
+// left unpublished rather than delaying notifications.
+func (c *crossposter) enqueue(job crosspostJob) {
+	select {
+	case c.jobs <- job:
+	default:
+		log.Printf("Crosspost queue full, not publishing message %s in channel %s", job.MessageID, job.ChannelID)
+	}
+}
+
+// retryAfter re-enqueues a job once the delay has passed.
+func (c *crossposter) retryAfter(job crosspostJob, delay time.Duration) {
+	c.clock.AfterFunc(delay, func() { c.enqueue(job) })
+}
+
+func (c *crossposter) run() {
+	for job := range c.jobs {
+		if wait := c.reserve(job.ChannelID); wait > 0 {
+			log.Printf("Crosspost limit reached in channel %s, publishing message %s in %s", job.ChannelID, job.MessageID, wait.Round(time.Second))
+			c.retryAfter(job, wait)
+			continue
+		}
+		c.crosspost(job)
+	}
+}
+
+// reserve records a crosspost for the channel if the hourly limit allows it and
+// otherwise returns how long until a slot frees up.
+func (c *crossposter) reserve(channelID string) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	recent := c.sent[channelID][:0]
+	for _, t := range c.sent[channelID] {
+		if now.Sub(t) < crosspostWindow {
+			recent = append(recent, t)
+		}
+	}
+
+	if len(recent) >= crosspostLimit {
+		c.sent[channelID] = recent
+		return crosspostWindow - now.Sub(recent[0])
+	}
+
+	c.sent[channelID] = append(recent, now)
+	return 0
+}
+
+func (c *crossposter) crosspost(job crosspostJob) {
+	job.Attempts++
+	_, err := c.discord.ChannelMessageCrosspost(job.ChannelID, job.MessageID, discordgo.WithRetryOnRatelimit(false))
+	if err == nil {
+		return
+	}
+
+	var rateLimitErr *discordgo.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		log.Printf("Crosspost rate limited in channel %s, retrying message %s in %s", job.ChannelID, job.MessageID, rateLimitErr.RetryAfter)
+		job.Attempts--
+		c.retryAfter(job, rateLimitErr.RetryAfter)
+		return
+	}
+
+	switch discordErrorCode(err) {
+	case discordgo.ErrCodeMessageAlreadyCrossposted, discordgo.ErrCodeUnknownMessage:
+		return
+	case discordgo.ErrCodeMissingAccess, discordgo.ErrCodeMissingPermissions:
+		log.Printf("Missing permission to publish %s notification in channel %s (guild %s): %v", job.Username, job.ChannelID, job.GuildID, err)
+		return
+	}
+
+	if job.Attempts >= crosspostMaxAttempts {
+		log.Printf("Giving up publishing message %s in channel %s after %d attempts: %v", job.MessageID, job.ChannelID, job.Attempts, err)
+		return
+	}
+
+	log.Printf("Error publishing message %s in channel %s, retrying: %v", job.MessageID, job.ChannelID, err)
+	c.retryAfter(job, time.Duration(job.Attempts)*time.Minute)
+}
+
+// crosspostNotification queues an announcement channel notification to be
+// published when the subscription has auto-crosspost enabled.
+func (b *Bot) crosspostNotification(n notification, channel *discordgo.Channel, msg *discordgo.Message) {
+	if msg == nil || !n.User.AutoCrosspost || channel.Type != discordgo.ChannelTypeGuildNews {
+		return
+	}
+
+	b.crossposts.enqueue(crosspostJob{
+		GuildID:   n.User.GuildID,
+		ChannelID: channel.ID,
+		MessageID: msg.ID,
+		Username:  n.User.Username,
+	})
+}
@@ -0,0 +1,149 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/fvckgrimm/discord-fansly-notify/api"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/embed"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+// queueDigestPost stores a detected post so it can be delivered in the subscription's next digest.
+func (b *Bot) queueDigestPost(user models.MonitoredUser, post api.Post) error {
+	return b.Repo.AddPendingPost(&models.PendingPost{
+		GuildID:  user.GuildID,
+		UserID:   user.UserID,
+		PostID:   post.ID,
+		Content:  post.Content,
+		PostedAt: post.CreatedAt,
+		QueuedAt: b.Clock.Now().Unix(),
+	})
+}
+
+// flushDueDigests sends one summary per subscription whose digest window has closed.
+// Posts of guilds the bot has left stay queued until the guild is restored or purged.
+func (b *Bot) flushDueDigests(now time.Time) {
+	pending, err := b.Repo.GetPendingPosts()
+	if err != nil {
+		log.Printf("Error fetching pending digest posts: %v", err)
+		return
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	type subscriptionKey struct{ guildID, userID string }
+	groups := make(map[subscriptionKey][]models.PendingPost)
+	var order []subscriptionKey
+	for _, post := range pending {
+		key := subscriptionKey{post.GuildID, post.UserID}
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], post)
+	}
+
+	settingsCache := make(map[string]*models.GuildSettings)
+
+	for _, key := range order {
+		posts := groups[key]
+
+		user, err := b.Repo.GetMonitoredUser(key.guildID, key.userID)
+		if err != nil {
+			log.Printf("Error fetching subscription for digest in guild %s: %v", key.guildID, err)
+			continue
+		}
+		if user == nil {
+			// The creator was removed from this guild; the queued posts have nowhere to go.
+			if err := b.Repo.DeletePendingPostsForUser(key.guildID, key.userID); err != nil {
+				log.Printf("Error discarding orphaned digest posts for guild %s: %v", key.guildID, err)
+			}
+			continue
+		}
+
+		settings, ok := settingsCache[key.guildID]
+		if !ok {
+			settings, err = b.Repo.GetGuildSettings(key.guildID)
+			if err != nil {
+				log.Printf("Error fetching settings for guild %s: %v", key.guildID, err)
+				continue
+			}
+			settingsCache[key.guildID] = settings
+		}
+
+		boundary := digestBoundary(user.DeliveryMode(), settings.Location(), settings.DigestHour, now)
+
+		var due []models.PendingPost
+		var dueIDs []uint
+		for _, post := range posts {
+			if post.QueuedAt < boundary.Unix() {
+				due = append(due, post)
+				dueIDs = append(dueIDs, post.ID)
+			}
+		}
+		if len(due) == 0 {
+			continue
+		}
+
+		if err := b.sendDigest(*user, due); err != nil {
+			// Keep the posts queued so the digest is retried on the next tick.
+			continue
+		}
+
+		if err := b.Repo.DeletePendingPosts(dueIDs); err != nil {
+			log.Printf("Error clearing delivered digest posts for %s in guild %s: %v", user.Username, user.GuildID, err)
+		}
+	}
+}
+
+func (b *Bot) sendDigest(user models.MonitoredUser, posts []models.PendingPost) error {
+	mode := user.DeliveryMode()
+	if mode == models.DeliveryInstant {
+		// Posts left over after switching back to instant are flushed as a single summary.
+		mode = "pending"
+	}
+	embedMsg := embed.CreateDigestEmbed(user.Username, posts, user.AvatarLocation, mode)
+
+	var mention string
+	if user.PostMentionRole != "" {
+		mention = fmt.Sprintf("<@&%s>", user.PostMentionRole)
+	}
+
+	targetChannel := user.PostNotificationChannel
+	if targetChannel == "" {
+		targetChannel = user.NotificationChannel
+	}
+
+	log.Printf("Sending %s digest with %d posts for %s to guild %s", mode, len(posts), user.Username, user.GuildID)
+
+	return b.deliverNotification(notification{
+		Kind:    kindDigest,
+		User:    user,
+		Channel: targetChannel,
+		Mention: mention,
+		Embed:   embedMsg,
+	})
+}
+
+// digestBoundary returns the start of the current digest window. Posts queued
+// before it are due. Hourly digests close at the top of every hour and daily
+// digests at digestHour, both in the guild's timezone.
+func digestBoundary(mode string, loc *time.Location, digestHour int, now time.Time) time.Time {
+	local := now.In(loc)
+
+	switch mode {
+	case models.DeliveryHourly:
+		return time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), 0, 0, 0, loc)
+	case models.DeliveryDaily:
+		boundary := time.Date(local.Year(), local.Month(), local.Day(), digestHour, 0, 0, 0, loc)
+		if boundary.After(local) {
+			boundary = boundary.AddDate(0, 0, -1)
+		}
+		return boundary
+	default:
+		// Instant subscriptions flush anything still queued right away.
+		return now.Add(time.Second)
+	}
+}
@@ -0,0 +1,49 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+func TestDigestBoundary(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       string
+		timezone   string
+		digestHour int
+		now        string
+		want       string
+	}{
+		{"hourly", models.DeliveryHourly, "UTC", 9, "2024-05-01T12:34:56Z", "2024-05-01T12:00:00Z"},
+		{"hourly on the hour", models.DeliveryHourly, "UTC", 9, "2024-05-01T12:00:00Z", "2024-05-01T12:00:00Z"},
+		// India is UTC+5:30, so its hours start at half past in UTC.
+		{"hourly half hour offset", models.DeliveryHourly, "Asia/Kolkata", 9, "2024-05-01T12:10:00Z", "2024-05-01T11:30:00Z"},
+		{"daily after digest hour", models.DeliveryDaily, "UTC", 9, "2024-05-01T12:00:00Z", "2024-05-01T09:00:00Z"},
+		{"daily at digest hour", models.DeliveryDaily, "UTC", 9, "2024-05-01T09:00:00Z", "2024-05-01T09:00:00Z"},
+		{"daily before digest hour", models.DeliveryDaily, "UTC", 9, "2024-05-01T08:59:59Z", "2024-04-30T09:00:00Z"},
+		{"daily across midnight", models.DeliveryDaily, "UTC", 23, "2024-05-01T00:30:00Z", "2024-04-30T23:00:00Z"},
+		{"daily guild timezone", models.DeliveryDaily, "Asia/Tokyo", 9, "2024-05-01T01:00:00Z", "2024-05-01T00:00:00Z"},
+		{"daily guild timezone previous day", models.DeliveryDaily, "Asia/Tokyo", 9, "2024-04-30T23:00:00Z", "2024-04-30T00:00:00Z"},
+		// New York springs forward on 2024-03-10: 09:00 is 14:00 UTC the day
+		// before and 13:00 UTC that day.
+		{"daily before spring forward", models.DeliveryDaily, "America/New_York", 9, "2024-03-10T12:00:00Z", "2024-03-09T14:00:00Z"},
+		{"daily after spring forward", models.DeliveryDaily, "America/New_York", 9, "2024-03-10T13:00:00Z", "2024-03-10T13:00:00Z"},
+		{"instant", models.DeliveryInstant, "UTC", 9, "2024-05-01T12:00:00Z", "2024-05-01T12:00:01Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now, _ := time.Parse(time.RFC3339, tt.now)
+			want, _ := time.Parse(time.RFC3339, tt.want)
+			loc := models.GuildSettings{Timezone: tt.timezone}.Location()
+
+			got := digestBoundary(tt.mode, loc, tt.digestHour, now)
+			if !got.Equal(want) {
+				t.Errorf("digestBoundary(%s, %s, %d) at %s = %s, want %s",
+					tt.mode, tt.timezone, tt.digestHour, tt.now, got.UTC().Format(time.RFC3339), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/config"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/embed"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/events"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+// maxDMFailures is how many DMs in a row may fail before a member's
+// subscriptions are removed, usually because they closed their DMs.
+const maxDMFailures = 3
+
+func (b *Bot) deliverPostToSubscribers(e events.PostPublished) {
+	if len(e.Subscriptions) == 0 {
+		return
+	}
+	user := e.Subscriptions[0]
+	b.sendSubscriberDMs(e.CreatorID, e.Subscriptions,
+		fmt.Sprintf("📬 New post from **%s**", user.Username),
+		embed.CreatePostEmbed(user.Username, e.Post, user.AvatarLocation, nil))
+}
+
+func (b *Bot) deliverStreamToSubscribers(e events.StreamStarted) {
+	if len(e.Subscriptions) == 0 {
+		return
+	}
+	user := e.Subscriptions[0]
+	b.sendSubscriberDMs(e.CreatorID, e.Subscriptions,
+		fmt.Sprintf("🔴 **%s** is live", user.Username),
+		embed.CreateLiveStreamEmbed(user.Username, e.Stream, user.AvatarLocation, user.LiveImageURL))
+}
+
+// sendSubscriberDMs messages every member subscribed to the creator through one
+// of the notified guilds. Members subscribed through several guilds get a
+// single DM. Sending happens in the background like sink delivery.
+func (b *Bot) sendSubscriberDMs(creatorID string, notified []models.MonitoredUser, content string, embedMsg *discordgo.MessageEmbed) {
+	go func() {
+		subs, err := b.Repo.GetSubscribersForCreator(creatorID)
+		if err != nil {
+			log.Printf("Error fetching DM subscribers for creator %s: %v", creatorID, err)
+			return
+		}
+
+		guilds := make(map[string]bool, len(notified))
+		for _, user := range notified {
+			guilds[user.GuildID] = true
+		}
+
+		sent := make(map[string]bool)
+		for _, sub := range subs {
+			if !guilds[sub.GuildID] || sent[sub.DiscordUserID] {
+				continue
+			}
+			sent[sub.DiscordUserID] = true
+			b.sendSubscriberDM(sub, content, embedMsg)
+		}
+	}()
+}
+
+func (b *Bot) sendSubscriberDM(sub models.UserSubscription, content string, embedMsg *discordgo.MessageEmbed) {
+	channel, err := b.Discord.UserChannelCreate(sub.DiscordUserID)
+	if err == nil {
+		_, err = b.Discord.ChannelMessageSendComplex(channel.ID, &discordgo.MessageSend{
+			Content: content,
+			Embed:   embedMsg,
+		})
+	}
+
+	if err == nil {
+		if sub.Failures > 0 {
+			if err := b.Repo.UpdateUserSubscriptionFailures(sub.DiscordUserID, 0); err != nil {
+				log.Printf("Error resetting DM failures for member %s: %v", sub.DiscordUserID, err)
+			}
+		}
+		return
+	}
+
+	if discordErrorCode(err) != discordgo.ErrCodeCannotSendMessagesToThisUser {
+		log.Printf("Error sending DM to member %s: %v", sub.DiscordUserID, err)
+		return
+	}
+
+	failures := sub.Failures + 1
+	if failures < maxDMFailures {
+		if err := b.Repo.UpdateUserSubscriptionFailures(sub.DiscordUserID, failures); err != nil {
+			log.Printf("Error recording DM failure for member %s: %v", sub.DiscordUserID, err)
+		}
+		return
+	}
+
+	log.Printf("DMs to member %s failed %d times in a row, removing their subscriptions", sub.DiscordUserID, failures)
+	if err := b.Repo.DeleteUserSubscriptionsForDiscordUser(sub.DiscordUserID); err != nil {
+		log.Printf("Error removing subscriptions for member %s: %v", sub.DiscordUserID, err)
+	}
+}
+
+// interactionUserID returns the ID of the member or user who triggered the interaction.
+func interactionUserID(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
+
+func (b *Bot) handleSubscribeCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	username := opts.String("username")
+	memberID := interactionUserID(i)
+
+	creator, err := b.Repo.GetMonitoredUserByUsername(i.GuildID, username)
+	if err != nil || creator == nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("**%s** isn't monitored in this server. Ask an admin to add them first.", username))
+		return
+	}
+
+	existing, err := b.Repo.GetUserSubscription(memberID, i.GuildID, creator.UserID)
+	if err != nil {
+		b.editInteractionResponse(s, i, "An error occurred. Please try again later.")
+		return
+	}
+	if existing != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("You're already subscribed to **%s**.", creator.Username))
+		return
+	}
+
+	if config.MaxSubscriptionsPerUser > 0 {
+		count, err := b.Repo.CountUserSubscriptions(memberID)
+		if err != nil {
+			b.editInteractionResponse(s, i, "An error occurred. Please try again later.")
+			return
+		}
+		if count >= int64(config.MaxSubscriptionsPerUser) {
+			b.editInteractionResponse(s, i, fmt.Sprintf("You've reached the limit of %d subscriptions. Use `/unsubscribe` to make room.", config.MaxSubscriptionsPerUser))
+			return
+		}
+	}
+
+	err = b.Repo.AddUserSubscription(&models.UserSubscription{
+		DiscordUserID: memberID,
+		GuildID:       i.GuildID,
+		CreatorID:     creator.UserID,
+		CreatedAt:     b.Clock.Now().Unix(),
+	})
+	if err != nil {
+		log.Printf("Error adding DM subscription for member %s: %v", memberID, err)
+		b.editInteractionResponse(s, i, "An error occurred. Please try again later.")
+		return
+	}
+
+	b.editInteractionResponse(s, i, fmt.Sprintf("✅ I'll DM you when **%s** posts or goes live. Make sure you allow DMs from members of this server.", creator.Username))
+}
+
+func (b *Bot) handleUnsubscribeCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	username := opts.String("username")
+	memberID := interactionUserID(i)
+
+	creator, err := b.Repo.GetMonitoredUserByUsername(i.GuildID, username)
+	if err != nil || creator == nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("You aren't subscribed to **%s** in this server.", username))
+		return
+	}
+
+	if err := b.Repo.DeleteUserSubscription(memberID, i.GuildID, creator.UserID); err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("You aren't subscribed to **%s** in this server.", creator.Username))
+		return
+	}
+
+	b.editInteractionResponse(s, i, fmt.Sprintf("You will no longer get DMs for **%s**.", creator.Username))
+}
+
+func (b *Bot) handleMySubscriptionsCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	subs, err := b.Repo.GetUserSubscriptions(interactionUserID(i))
+	if err != nil {
+		b.editInteractionResponse(s, i, "An error occurred. Please try again later.")
+		return
+	}
+	if len(subs) == 0 {
+		b.editInteractionResponse(s, i, "You have no subscriptions. Use `/subscribe` to get DMs for a creator.")
+		return
+	}
+
+	lines := make([]string, 0, len(subs))
+	for _, sub := range subs {
+		name := sub.CreatorID
+		if creator, err := b.Repo.GetMonitoredUser(sub.GuildID, sub.CreatorID); err == nil && creator != nil {
+			name = creator.Username
+		}
+		guildName := sub.GuildID
+		if guild, err := s.State.Guild(sub.GuildID); err == nil {
+			guildName = guild.Name
+		}
+		lines = append(lines, fmt.Sprintf("• **%s** (via %s)", name, guildName))
+	}
+
+	limit := ""
+	if config.MaxSubscriptionsPerUser > 0 {
+		limit = fmt.Sprintf(" (%d/%d)", len(subs), config.MaxSubscriptionsPerUser)
+	}
+	b.editInteractionResponse(s, i, fmt.Sprintf("Your subscriptions%s:\n%s", limit, strings.Join(lines, "\n")))
+}
@@ -0,0 +1,240 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/config"
+)
+
+// FollowReport describes one reconciliation of the bot account's follows
+// with the monitored creators.
+type FollowReport struct {
+	DryRun    bool
+	Following int // accounts followed before reconciling
+	Monitored int // distinct monitored creators
+
+	// Missing are monitored creators the account didn't follow; Followed are
+	// the ones it followed since.
+	Missing  []string
+	Followed []string
+	// Stale are followed creators past the unfollow grace period; Unfollowed
+	// are the ones it unfollowed since.
+	Stale      []string
+	Unfollowed []string
+	// Pending are followed creators nobody monitors that are still within the
+	// grace period.
+	Pending []PendingUnfollow
+	Errors  []string
+}
+
+// PendingUnfollow is a creator that will be unfollowed at UnfollowAt unless a
+// guild monitors them again.
+type PendingUnfollow struct {
+	CreatorID  string
+	UnfollowAt time.Time
+}
+
+// ReconcileFollows makes the bot's Fansly account follow every monitored
+// creator and unfollows creators nobody has monitored for the grace period.
+// With dryRun it only reports what it would do.
+func (b *Bot) ReconcileFollows(dryRun bool) (*FollowReport, error) {
+	account, err := b.APIClient.GetMyAccountInfo()
+	if err != nil {
+		return nil, fmt.Errorf("fetching the bot's account: %w", err)
+	}
+	following, err := b.APIClient.GetFollowing(account.ID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching followed accounts: %w", err)
+	}
+	users, err := b.Repo.GetMonitoredUsers()
+	if err != nil {
+		return nil, fmt.Errorf("fetching monitored creators: %w", err)
+	}
+	marks, err := b.Repo.GetUnmonitoredCreators()
+	if err != nil {
+		return nil, fmt.Errorf("fetching unmonitored creators: %w", err)
+	}
+
+	followed := make(map[string]bool, len(following))
+	for _, f := range following {
+		followed[f.AccountID] = true
+	}
+	monitored := make(map[string]string)
+	for _, user := range users {
+		monitored[user.UserID] = user.Username
+	}
+
+	report := &FollowReport{DryRun: dryRun, Following: len(following), Monitored: len(monitored)}
+
+	creatorIDs := make([]string, 0, len(monitored))
+	for creatorID := range monitored {
+		creatorIDs = append(creatorIDs, creatorID)
+	}
+	sort.Strings(creatorIDs)
+
+	for _, creatorID := range creatorIDs {
+		if followed[creatorID] {
+			continue
+		}
+		username := monitored[creatorID]
+		report.Missing = append(report.Missing, username)
+		if dryRun {
+			continue
+		}
+		if err := b.APIClient.FollowAccount(creatorID); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("follow %s: %v", username, err))
+			continue
+		}
+		report.Followed = append(report.Followed, username)
+	}
+
+	grace := time.Duration(config.UnfollowGraceHours) * time.Hour
+	now := b.Clock.Now()
+	for _, mark := range marks {
+		_, isMonitored := monitored[mark.CreatorID]
+		unfollowAt := time.Unix(mark.Since, 0).Add(grace)
+
+		switch {
+		case isMonitored || !followed[mark.CreatorID]:
+			// Monitored again, or there is nothing to unfollow.
+			if !dryRun {
+				b.clearUnmonitoredMark(mark.CreatorID)
+			}
+		case now.Before(unfollowAt):
+			report.Pending = append(report.Pending, PendingUnfollow{CreatorID: mark.CreatorID, UnfollowAt: unfollowAt})
+		default:
+			name := b.creatorDisplayName(mark.CreatorID)
+			report.Stale = append(report.Stale, name)
+			if dryRun {
+				continue
+			}
+			if err := b.APIClient.UnfollowAccount(mark.CreatorID); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("unfollow %s: %v", name, err))
+				continue
+			}
+			b.clearUnmonitoredMark(mark.CreatorID)
+			report.Unfollowed = append(report.Unfollowed, name)
+		}
+	}
+
+	return report, nil
+}
+
+func (b *Bot) clearUnmonitoredMark(creatorID string) {
+	if err := b.Repo.DeleteUnmonitoredCreator(creatorID); err != nil {
+		log.Printf("Error clearing unmonitored mark of creator %s: %v", creatorID, err)
+	}
+}
+
+// creatorDisplayName returns the creator's current username, or the ID when
+// it can't be looked up.
+func (b *Bot) creatorDisplayName(creatorID string) string {
+	account, err := b.APIClient.GetAccountInfoByID(creatorID)
+	if err != nil || account.Username == "" {
+		return creatorID
+	}
+	return account.Username
+}
+
+// markUnmonitoredCreators starts the unfollow grace period of creators that no
+// guild monitors anymore.
+func (b *Bot) markUnmonitoredCreators(creatorIDs []string) {
+	now := b.Clock.Now().Unix()
+	for _, creatorID := range creatorIDs {
+		if err := b.Repo.MarkCreatorUnmonitored(creatorID, now); err != nil {
+			log.Printf("Error marking creator %s as unmonitored: %v", creatorID, err)
+		}
+	}
+}
+
+func (b *Bot) reconcileFollowsPeriodically() {
+	if config.FollowReconcileHours <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(config.FollowReconcileHours) * time.Hour)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		report, err := b.ReconcileFollows(false)
+		if err != nil {
+			log.Printf("Error reconciling follows: %v", err)
+			continue
+		}
+		log.Printf("Reconciled follows: followed %d, unfollowed %d, %d pending unfollow, %d errors",
+			len(report.Followed), len(report.Unfollowed), len(report.Pending), len(report.Errors))
+		for _, message := range report.Errors {
+			log.Printf("Follow reconciliation error: %s", message)
+		}
+	}
+}
+
+func (b *Bot) handleFollowsCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	report, err := b.ReconcileFollows(opts.Bool("dry_run"))
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error checking follows: %v", err))
+		return
+	}
+	b.editInteractionResponse(s, i, formatFollowReport(report))
+}
+
+// formatFollowReport renders the report for /owner follows, keeping it
+// within a message.
+func formatFollowReport(r *FollowReport) string {
+	const maxNames = 20
+
+	names := func(list []string) string {
+		if len(list) > maxNames {
+			return strings.Join(list[:maxNames], ", ") + fmt.Sprintf(" and %d more", len(list)-maxNames)
+		}
+		return strings.Join(list, ", ")
+	}
+
+	title := "**Follow reconciliation**"
+	if r.DryRun {
+		title += " (dry run, nothing changed)"
+	}
+	lines := []string{
+		title,
+		fmt.Sprintf("The bot's account follows %d accounts; %d creators are monitored.", r.Following, r.Monitored),
+	}
+
+	if len(r.Missing) == 0 {
+		lines = append(lines, "✅ Every monitored creator is followed.")
+	} else {
+		lines = append(lines, fmt.Sprintf("➕ Not followed: %s", names(r.Missing)))
+		if !r.DryRun {
+			lines = append(lines, fmt.Sprintf("  Followed %d of %d.", len(r.Followed), len(r.Missing)))
+		}
+	}
+
+	if len(r.Stale) > 0 {
+		lines = append(lines, fmt.Sprintf("➖ No longer monitored: %s", names(r.Stale)))
+		if !r.DryRun {
+			lines = append(lines, fmt.Sprintf("  Unfollowed %d of %d.", len(r.Unfollowed), len(r.Stale)))
+		}
+	}
+
+	if len(r.Pending) > 0 {
+		pending := make([]string, 0, min(len(r.Pending), maxNames))
+		for _, p := range r.Pending[:min(len(r.Pending), maxNames)] {
+			pending = append(pending, fmt.Sprintf("%s <t:%d:R>", p.CreatorID, p.UnfollowAt.Unix()))
+		}
+		line := fmt.Sprintf("⏳ Will be unfollowed: %s", strings.Join(pending, ", "))
+		if len(r.Pending) > maxNames {
+			line += fmt.Sprintf(" and %d more", len(r.Pending)-maxNames)
+		}
+		lines = append(lines, line)
+	}
+
+	for _, message := range r.Errors[:min(len(r.Errors), 5)] {
+		lines = append(lines, fmt.Sprintf("⚠️ %s", truncateError(message)))
+	}
+
+	return strings.Join(lines, "\n")
+}
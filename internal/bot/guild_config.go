@@ -0,0 +1,679 @@
+package bot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/config"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+// guildExportVersion is bumped whenever the export format changes in a way
+// older versions of the bot can't read.
+const guildExportVersion = 1
+
+const (
+	maxImportFileSize = 1 << 20
+	importExpiry      = 10 * time.Minute
+	maxImportDiff     = 40
+	importPrefix      = "import:"
+)
+
+// guildExport is the JSON file written by /export. It holds configuration
+// only: DM subscriptions belong to members and outbound webhooks hold
+// secrets, so neither is exported.
+type guildExport struct {
+	Version     int               `json:"version"`
+	GuildID     string            `json:"guild_id"`
+	GuildName   string            `json:"guild_name"`
+	ExportedAt  int64             `json:"exported_at"`
+	Settings    exportedSettings  `json:"settings"`
+	Creators    []exportedCreator `json:"creators"`
+	Permissions []exportedGrant   `json:"permissions"`
+}
+
+type exportedSettings struct {
+	Timezone               string `json:"timezone"`
+	DigestHour             int    `json:"digest_hour"`
+	QuietHoursEnabled      bool   `json:"quiet_hours_enabled"`
+	QuietStart             int    `json:"quiet_start"`
+	QuietEnd               int    `json:"quiet_end"`
+	QuietMode              string `json:"quiet_mode"`
+	ScheduledEventsEnabled bool   `json:"scheduled_events_enabled"`
+	AnnounceRenames        bool   `json:"announce_renames"`
+}
+
+type exportedCreator struct {
+	UserID           string `json:"user_id"`
+	Username         string `json:"username"`
+	PostChannel      string `json:"post_channel"`
+	LiveChannel      string `json:"live_channel"`
+	PostMentionRole  string `json:"post_mention_role,omitempty"`
+	LiveMentionRole  string `json:"live_mention_role,omitempty"`
+	LiveImageURL     string `json:"live_image_url,omitempty"`
+	PostsEnabled     bool   `json:"posts_enabled"`
+	PostsSuspended   bool   `json:"posts_suspended,omitempty"`
+	LiveEnabled      bool   `json:"live_enabled"`
+	PostDeliveryMode string `json:"post_delivery_mode"`
+	MutedUntil       int64  `json:"muted_until,omitempty"`
+	WebhookDelivery  bool   `json:"webhook_delivery"`
+	ThreadPosts      bool   `json:"thread_posts"`
+	ThreadLive       bool   `json:"thread_live"`
+	AutoCrosspost    bool   `json:"auto_crosspost"`
+	NotifyButtons    bool   `json:"notify_buttons"`
+	NotifyRoleID     string `json:"notify_role_id,omitempty"`
+	LastPostID       string `json:"last_post_id,omitempty"`
+	LastStreamStart  int64  `json:"last_stream_start,omitempty"`
+}
+
+type exportedGrant struct {
+	TargetType string `json:"target_type"`
+	TargetID   string `json:"target_id"`
+	Scope      string `json:"scope"`
+}
+
+func exportCreator(user models.MonitoredUser) exportedCreator {
+	postChannel := user.PostNotificationChannel
+	if postChannel == "" {
+		postChannel = user.NotificationChannel
+	}
+	liveChannel := user.LiveNotificationChannel
+	if liveChannel == "" {
+		liveChannel = user.NotificationChannel
+	}
+
+	return exportedCreator{
+		UserID:           user.UserID,
+		Username:         user.Username,
+		PostChannel:      postChannel,
+		LiveChannel:      liveChannel,
+		PostMentionRole:  user.PostMentionRole,
+		LiveMentionRole:  user.LiveMentionRole,
+		LiveImageURL:     user.LiveImageURL,
+		PostsEnabled:     user.PostsEnabled,
+		PostsSuspended:   user.PostsSuspended,
+		LiveEnabled:      user.LiveEnabled,
+		PostDeliveryMode: user.DeliveryMode(),
+		MutedUntil:       user.MutedUntil,
+		WebhookDelivery:  user.WebhookDelivery,
+		ThreadPosts:      user.ThreadPosts,
+		ThreadLive:       user.ThreadLive,
+		AutoCrosspost:    user.AutoCrosspost,
+		NotifyButtons:    user.NotifyButtons,
+		NotifyRoleID:     user.NotifyRoleID,
+		LastPostID:       user.LastPostID,
+		LastStreamStart:  user.LastStreamStart,
+	}
+}
+
+// applyTo copies the exported configuration onto a subscription, keeping its
+// monitoring state.
+func (c exportedCreator) applyTo(user *models.MonitoredUser) {
+	user.Username = c.Username
+	user.NotificationChannel = c.PostChannel
+	user.PostNotificationChannel = c.PostChannel
+	user.LiveNotificationChannel = c.LiveChannel
+	user.MentionRole = c.PostMentionRole
+	user.PostMentionRole = c.PostMentionRole
+	user.LiveMentionRole = c.LiveMentionRole
+	user.LiveImageURL = c.LiveImageURL
+	user.PostsEnabled = c.PostsEnabled
+	user.PostsSuspended = c.PostsSuspended
+	user.LiveEnabled = c.LiveEnabled
+	user.PostDeliveryMode = c.PostDeliveryMode
+	user.MutedUntil = c.MutedUntil
+	user.WebhookDelivery = c.WebhookDelivery
+	user.ThreadPosts = c.ThreadPosts
+	user.ThreadLive = c.ThreadLive
+	user.AutoCrosspost = c.AutoCrosspost
+	user.NotifyButtons = c.NotifyButtons
+	user.NotifyRoleID = c.NotifyRoleID
+}
+
+func exportSettings(settings models.GuildSettings) exportedSettings {
+	return exportedSettings{
+		Timezone:               settings.Timezone,
+		DigestHour:             settings.DigestHour,
+		QuietHoursEnabled:      settings.QuietHoursEnabled,
+		QuietStart:             settings.QuietStart,
+		QuietEnd:               settings.QuietEnd,
+		QuietMode:              settings.QuietMode,
+		ScheduledEventsEnabled: settings.ScheduledEventsEnabled,
+		AnnounceRenames:        settings.AnnounceRenames,
+	}
+}
+
+func (e exportedSettings) toModel(guildID string) models.GuildSettings {
+	return models.GuildSettings{
+		GuildID:                guildID,
+		Timezone:               e.Timezone,
+		DigestHour:             e.DigestHour,
+		QuietHoursEnabled:      e.QuietHoursEnabled,
+		QuietStart:             e.QuietStart,
+		QuietEnd:               e.QuietEnd,
+		QuietMode:              e.QuietMode,
+		ScheduledEventsEnabled: e.ScheduledEventsEnabled,
+		AnnounceRenames:        e.AnnounceRenames,
+	}
+}
+
+// validate rejects files this version can't import or that were edited into
+// an inconsistent state.
+func (e *guildExport) validate() error {
+	if e.Version < 1 || e.Version > guildExportVersion {
+		return fmt.Errorf("unsupported export version %d, this bot reads version %d", e.Version, guildExportVersion)
+	}
+
+	if _, err := time.LoadLocation(e.Settings.Timezone); e.Settings.Timezone != "" && err != nil {
+		return fmt.Errorf("unknown timezone %q", e.Settings.Timezone)
+	}
+	if e.Settings.DigestHour < 0 || e.Settings.DigestHour > 23 {
+		return fmt.Errorf("digest hour %d is out of range", e.Settings.DigestHour)
+	}
+	if e.Settings.QuietStart < 0 || e.Settings.QuietStart >= 24*60 || e.Settings.QuietEnd < 0 || e.Settings.QuietEnd >= 24*60 {
+		return fmt.Errorf("quiet hours are out of range")
+	}
+	switch e.Settings.QuietMode {
+	case "", models.QuietModeSuppress, models.QuietModeQueue:
+	default:
+		return fmt.Errorf("unknown quiet hours mode %q", e.Settings.QuietMode)
+	}
+
+	seen := make(map[string]bool, len(e.Creators))
+	for _, creator := range e.Creators {
+		if creator.UserID == "" || creator.Username == "" {
+			return fmt.Errorf("every creator needs a user_id and a username")
+		}
+		if seen[creator.UserID] {
+			return fmt.Errorf("creator %s is listed twice", creator.Username)
+		}
+		seen[creator.UserID] = true
+		switch creator.PostDeliveryMode {
+		case "", models.DeliveryInstant, models.DeliveryHourly, models.DeliveryDaily:
+		default:
+			return fmt.Errorf("creator %s has unknown delivery mode %q", creator.Username, creator.PostDeliveryMode)
+		}
+	}
+
+	for _, grant := range e.Permissions {
+		if grant.TargetType != models.GrantTargetRole && grant.TargetType != models.GrantTargetUser {
+			return fmt.Errorf("unknown permission target type %q", grant.TargetType)
+		}
+		if models.ScopeLevel(grant.Scope) == 0 {
+			return fmt.Errorf("unknown permission scope %q", grant.Scope)
+		}
+	}
+
+	return nil
+}
+
+func (b *Bot) handleExportCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	users, err := b.Repo.GetMonitoredUsersForGuild(i.GuildID)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching monitored users: %v", err))
+		return
+	}
+	settings, err := b.Repo.GetGuildSettings(i.GuildID)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching server settings: %v", err))
+		return
+	}
+	grants, err := b.Repo.GetPermissionGrants(i.GuildID)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching permissions: %v", err))
+		return
+	}
+
+	export := guildExport{
+		Version:     guildExportVersion,
+		GuildID:     i.GuildID,
+		ExportedAt:  b.Clock.Now().Unix(),
+		Settings:    exportSettings(*settings),
+		Creators:    make([]exportedCreator, 0, len(users)),
+		Permissions: make([]exportedGrant, 0, len(grants)),
+	}
+	if guild, err := s.State.Guild(i.GuildID); err == nil {
+		export.GuildName = guild.Name
+	}
+	for _, user := range users {
+		export.Creators = append(export.Creators, exportCreator(user))
+	}
+	for _, grant := range grants {
+		export.Permissions = append(export.Permissions, exportedGrant{
+			TargetType: grant.TargetType,
+			TargetID:   grant.TargetID,
+			Scope:      grant.Scope,
+		})
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error creating the export: %v", err))
+		return
+	}
+
+	content := fmt.Sprintf("Exported %d creators, the server settings and %d permission grants. Use `/import` with this file to restore them.",
+		len(export.Creators), len(export.Permissions))
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content: &content,
+		Files: []*discordgo.File{{
+			Name:        fmt.Sprintf("fansly-notify-%s.json", i.GuildID),
+			ContentType: "application/json",
+			Reader:      bytes.NewReader(data),
+		}},
+	})
+	if err != nil {
+		log.Printf("Error sending export for guild %s: %v", i.GuildID, err)
+	}
+}
+
+// importPlan is a validated import, remapped to this guild, waiting for confirmation.
+type importPlan struct {
+	GuildID  string
+	MemberID string
+	Users    []models.MonitoredUser
+	Settings *models.GuildSettings // nil when unchanged
+	Grants   []models.PermissionGrant
+	Expires  time.Time
+}
+
+// pendingImports holds import plans until they are confirmed. They don't
+// survive restarts; the member simply runs /import again.
+type pendingImports struct {
+	mu    sync.Mutex
+	plans map[string]*importPlan
+}
+
+func newPendingImports() *pendingImports {
+	return &pendingImports{plans: make(map[string]*importPlan)}
+}
+
+func (p *pendingImports) add(id string, plan *importPlan) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for key, pending := range p.plans {
+		if time.Now().After(pending.Expires) {
+			delete(p.plans, key)
+		}
+	}
+	p.plans[id] = plan
+}
+
+// take removes and returns a plan that hasn't expired.
+func (p *pendingImports) take(id string) *importPlan {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	plan, ok := p.plans[id]
+	delete(p.plans, id)
+	if !ok || time.Now().After(plan.Expires) {
+		return nil
+	}
+	return plan
+}
+
+// guildRemapper replaces channels and roles that don't exist in the guild.
+type guildRemapper struct {
+	guildID         string
+	s               *discordgo.Session
+	fallbackChannel string
+	channels        map[string]string
+	roles           map[string]bool
+}
+
+func (m *guildRemapper) channel(id string) string {
+	if mapped, ok := m.channels[id]; ok {
+		return mapped
+	}
+	mapped := id
+	if channel, err := m.s.State.Channel(id); err != nil || channel.GuildID != m.guildID || !isSupportedNotificationChannel(channel) {
+		mapped = m.fallbackChannel
+	}
+	m.channels[id] = mapped
+	return mapped
+}
+
+// role returns the role if it still exists, or an empty string.
+func (m *guildRemapper) role(id string) string {
+	if id == "" {
+		return ""
+	}
+	if exists, ok := m.roles[id]; ok {
+		if exists {
+			return id
+		}
+		return ""
+	}
+	_, err := m.s.State.Role(m.guildID, id)
+	m.roles[id] = err == nil
+	if err != nil {
+		return ""
+	}
+	return id
+}
+
+// remapped lists the channels and roles that were replaced.
+func (m *guildRemapper) remapped() []string {
+	var lines []string
+	for from, to := range m.channels {
+		if from != to {
+			lines = append(lines, fmt.Sprintf("🔀 Channel `%s` no longer exists, using <#%s> instead", from, to))
+		}
+	}
+	for role, exists := range m.roles {
+		if !exists {
+			lines = append(lines, fmt.Sprintf("🔀 Role `%s` no longer exists, its mentions and grants are dropped", role))
+		}
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func (b *Bot) handleImportCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	attachment := i.ApplicationCommandData().Resolved.Attachments[opts.String("file")]
+	if attachment == nil {
+		b.editInteractionResponse(s, i, "Error: the attached file could not be read.")
+		return
+	}
+
+	export, err := readGuildExport(attachment)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error: %v", err))
+		return
+	}
+
+	fallback := i.ChannelID
+	if channel := opts.Channel(s, "channel"); channel != nil {
+		fallback = channel.ID
+	}
+
+	plan, diff, err := b.planImport(s, i.GuildID, interactionUserID(i), export, fallback)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error preparing the import: %v", err))
+		return
+	}
+	plan.Expires = time.Now().Add(importExpiry)
+
+	if len(plan.Users) == 0 && plan.Settings == nil && len(plan.Grants) == 0 {
+		b.editInteractionResponse(s, i, "Nothing to import: this server already matches the file.")
+		return
+	}
+
+	b.imports.add(i.ID, plan)
+
+	if len(diff) > maxImportDiff {
+		diff = append(diff[:maxImportDiff], fmt.Sprintf("…and %d more changes", len(diff)-maxImportDiff))
+	}
+	embeds := []*discordgo.MessageEmbed{{
+		Title:       "Import preview",
+		Description: strings.Join(diff, "\n"),
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: "Nothing has been changed yet. Creators that aren't in the file are kept.",
+		},
+		Color: 0x03b2f8,
+	}}
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Apply import",
+					Style:    discordgo.SuccessButton,
+					CustomID: importPrefix + "confirm:" + i.ID,
+				},
+				discordgo.Button{
+					Label:    "Cancel",
+					Style:    discordgo.SecondaryButton,
+					CustomID: importPrefix + "cancel:" + i.ID,
+				},
+			},
+		},
+	}
+
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds:     &embeds,
+		Components: &components,
+	})
+	if err != nil {
+		log.Printf("Error sending import preview for guild %s: %v", i.GuildID, err)
+	}
+}
+
+func readGuildExport(attachment *discordgo.MessageAttachment) (*guildExport, error) {
+	if strings.ToLower(path.Ext(attachment.Filename)) != ".json" {
+		return nil, fmt.Errorf("please attach the .json file created by /export")
+	}
+	if attachment.Size > maxImportFileSize {
+		return nil, fmt.Errorf("the file is larger than %d KB", maxImportFileSize>>10)
+	}
+
+	resp, err := attachmentClient.Get(attachment.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download the file: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download the file: status %d", resp.StatusCode)
+	}
+
+	var export guildExport
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxImportFileSize)).Decode(&export); err != nil {
+		return nil, fmt.Errorf("the file isn't a valid export: %v", err)
+	}
+	if err := export.validate(); err != nil {
+		return nil, fmt.Errorf("the file isn't a valid export: %v", err)
+	}
+	return &export, nil
+}
+
+// planImport compares the export with the guild's current configuration and
+// returns what would change, with missing channels and roles remapped.
+func (b *Bot) planImport(s *discordgo.Session, guildID, memberID string, export *guildExport, fallbackChannel string) (*importPlan, []string, error) {
+	plan := &importPlan{GuildID: guildID, MemberID: memberID}
+	remap := &guildRemapper{
+		guildID:         guildID,
+		s:               s,
+		fallbackChannel: fallbackChannel,
+		channels:        make(map[string]string),
+		roles:           make(map[string]bool),
+	}
+	var diff []string
+
+	existing, err := b.Repo.GetMonitoredUsersForGuild(guildID)
+	if err != nil {
+		return nil, nil, err
+	}
+	current := make(map[string]models.MonitoredUser, len(existing))
+	for _, user := range existing {
+		current[user.UserID] = user
+	}
+	count := len(existing)
+
+	for _, creator := range export.Creators {
+		creator.PostChannel = remap.channel(creator.PostChannel)
+		creator.LiveChannel = remap.channel(creator.LiveChannel)
+		creator.PostMentionRole = remap.role(creator.PostMentionRole)
+		creator.LiveMentionRole = remap.role(creator.LiveMentionRole)
+		creator.NotifyRoleID = remap.role(creator.NotifyRoleID)
+
+		user, exists := current[creator.UserID]
+		if !exists {
+			if config.MaxMonitoredUsersPerGuild > 0 && count >= config.MaxMonitoredUsersPerGuild {
+				diff = append(diff, fmt.Sprintf("⛔ **%s**: over this server's limit of %d creators, skipped", creator.Username, config.MaxMonitoredUsersPerGuild))
+				continue
+			}
+			count++
+			user = models.MonitoredUser{
+				GuildID:         guildID,
+				UserID:          creator.UserID,
+				LastPostID:      creator.LastPostID,
+				LastStreamStart: creator.LastStreamStart,
+			}
+			creator.applyTo(&user)
+			plan.Users = append(plan.Users, user)
+			diff = append(diff, fmt.Sprintf("➕ **%s**: new", creator.Username))
+			continue
+		}
+
+		before := exportCreator(user)
+		creator.applyTo(&user)
+		if changes := creatorChanges(before, exportCreator(user)); len(changes) > 0 {
+			plan.Users = append(plan.Users, user)
+			diff = append(diff, fmt.Sprintf("✏️ **%s**: %s", creator.Username, strings.Join(changes, ", ")))
+		}
+	}
+
+	settings, err := b.Repo.GetGuildSettings(guildID)
+	if err != nil {
+		return nil, nil, err
+	}
+	imported := export.Settings.toModel(guildID)
+	if imported.Timezone == "" {
+		imported.Timezone = models.DefaultTimezone
+	}
+	if changes := settingsChanges(*settings, imported); len(changes) > 0 {
+		plan.Settings = &imported
+		diff = append(diff, fmt.Sprintf("⚙️ Settings: %s", strings.Join(changes, ", ")))
+	}
+
+	grants, err := b.Repo.GetPermissionGrants(guildID)
+	if err != nil {
+		return nil, nil, err
+	}
+	currentScopes := make(map[string]string, len(grants))
+	for _, grant := range grants {
+		currentScopes[grant.TargetID] = grant.Scope
+	}
+	for _, grant := range export.Permissions {
+		if grant.TargetType == models.GrantTargetRole && remap.role(grant.TargetID) == "" {
+			continue
+		}
+		if currentScopes[grant.TargetID] == grant.Scope {
+			continue
+		}
+		plan.Grants = append(plan.Grants, models.PermissionGrant{
+			GuildID:    guildID,
+			TargetType: grant.TargetType,
+			TargetID:   grant.TargetID,
+			Scope:      grant.Scope,
+			GrantedBy:  memberID,
+			CreatedAt:  b.Clock.Now().Unix(),
+		})
+		diff = append(diff, fmt.Sprintf("🔑 %s: %s", formatGrantTarget(grant.TargetType, grant.TargetID), grant.Scope))
+	}
+
+	return plan, append(diff, remap.remapped()...), nil
+}
+
+// creatorChanges names the settings that differ between two subscriptions.
+func creatorChanges(before, after exportedCreator) []string {
+	var changes []string
+	check := func(changed bool, name string) {
+		if changed {
+			changes = append(changes, name)
+		}
+	}
+	check(before.PostChannel != after.PostChannel, "post channel")
+	check(before.LiveChannel != after.LiveChannel, "live channel")
+	check(before.PostMentionRole != after.PostMentionRole, "post mention")
+	check(before.LiveMentionRole != after.LiveMentionRole, "live mention")
+	check(before.LiveImageURL != after.LiveImageURL, "live image")
+	check(before.PostsEnabled != after.PostsEnabled, "posts")
+	check(before.LiveEnabled != after.LiveEnabled, "live")
+	check(before.PostDeliveryMode != after.PostDeliveryMode, "delivery")
+	check(before.MutedUntil != after.MutedUntil, "mute")
+	check(before.WebhookDelivery != after.WebhookDelivery, "identity")
+	check(before.ThreadPosts != after.ThreadPosts || before.ThreadLive != after.ThreadLive, "threads")
+	check(before.AutoCrosspost != after.AutoCrosspost, "crosspost")
+	check(before.NotifyButtons != after.NotifyButtons || before.NotifyRoleID != after.NotifyRoleID, "notify buttons")
+	return changes
+}
+
+// settingsChanges names the guild settings that differ.
+func settingsChanges(before, after models.GuildSettings) []string {
+	var changes []string
+	if before.Timezone != after.Timezone {
+		changes = append(changes, "timezone")
+	}
+	if before.DigestHour != after.DigestHour {
+		changes = append(changes, "digest hour")
+	}
+	if before.QuietHoursEnabled != after.QuietHoursEnabled || before.QuietStart != after.QuietStart ||
+		before.QuietEnd != after.QuietEnd || before.QuietMode != after.QuietMode {
+		changes = append(changes, "quiet hours")
+	}
+	if before.ScheduledEventsEnabled != after.ScheduledEventsEnabled {
+		changes = append(changes, "live events")
+	}
+	if before.AnnounceRenames != after.AnnounceRenames {
+		changes = append(changes, "rename announcements")
+	}
+	return changes
+}
+
+// handleImportButton applies or cancels a previewed import.
+func (b *Bot) handleImportButton(s *discordgo.Session, i *discordgo.InteractionCreate, data string) {
+	action, id, ok := strings.Cut(data, ":")
+	if !ok {
+		return
+	}
+
+	plan := b.imports.take(id)
+	if plan != nil && (plan.GuildID != i.GuildID || plan.MemberID != interactionUserID(i)) {
+		// Only the member who ran /import may answer; put the plan back.
+		b.imports.add(id, plan)
+		b.respondToInteraction(s, i, "Only the member who ran `/import` can use these buttons.", true)
+		return
+	}
+
+	content := "Import cancelled. Nothing was changed."
+	switch {
+	case plan == nil:
+		content = "This import preview has expired. Run `/import` again."
+	case action == "confirm":
+		if err := b.Repo.ImportGuildConfig(plan.Users, plan.Settings, plan.Grants); err != nil {
+			log.Printf("Error applying import for guild %s: %v", plan.GuildID, err)
+			content = fmt.Sprintf("Error applying the import, nothing was changed: %v", err)
+			break
+		}
+		log.Printf("Imported %d creators and %d grants into guild %s", len(plan.Users), len(plan.Grants), plan.GuildID)
+		content = fmt.Sprintf("✅ Import applied: %d creators updated, %d permission grants.", len(plan.Users), len(plan.Grants))
+		if plan.Settings != nil {
+			content += " Server settings were replaced."
+		}
+		go b.followImportedCreators(plan.Users)
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Embeds:     []*discordgo.MessageEmbed{},
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+	if err != nil {
+		log.Printf("Error updating import preview: %v", err)
+	}
+}
+
+// followImportedCreators makes sure the bot's account can read the timelines
+// of imported creators with post notifications, like /add does.
+func (b *Bot) followImportedCreators(users []models.MonitoredUser) {
+	follows := &followCache{}
+	for _, user := range users {
+		if user.PostsEnabled && !b.ensureTimelineAccess(user.UserID, user.Username, follows) {
+			log.Printf("Imported creator %s in guild %s has no timeline access", user.Username, user.GuildID)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package bot
+
+import (
+	"log"
+	"time"
+
+	"github.com/fvckgrimm/discord-fansly-notify/internal/config"
+)
+
+func guildRetention() time.Duration {
+	return time.Duration(config.GuildRetentionHours) * time.Hour
+}
+
+// orphanGuild keeps a guild's data after the bot is removed so it can be
+// restored if the bot is added back within the retention period.
+func (b *Bot) orphanGuild(guildID string) {
+	if config.GuildRetentionHours <= 0 {
+		b.purgeGuild(guildID)
+		return
+	}
+
+	if err := b.Repo.MarkGuildOrphaned(guildID, b.Clock.Now().Unix()); err != nil {
+		log.Printf("Error marking guild %s as orphaned: %v", guildID, err)
+		return
+	}
+	log.Printf("Keeping data for guild %s for %d hours in case the bot is added back", guildID, config.GuildRetentionHours)
+}
+
+// restoreGuild resumes monitoring for a guild the bot rejoined within the
+// retention period. Data of guilds rejoined later is purged first.
+func (b *Bot) restoreGuild(guildID, guildName string) {
+	orphan, err := b.Repo.GetOrphanedGuild(guildID)
+	if err != nil {
+		log.Printf("Error checking whether guild %s was orphaned: %v", guildID, err)
+		return
+	}
+	if orphan == nil {
+		return
+	}
+
+	if b.Clock.Now().Sub(time.Unix(orphan.OrphanedAt, 0)) > guildRetention() {
+		b.purgeGuild(guildID)
+		return
+	}
+
+	if err := b.Repo.RestoreOrphanedGuild(guildID); err != nil {
+		log.Printf("Error restoring guild %s: %v", guildID, err)
+		return
+	}
+	log.Printf("Bot rejoined %s (%s), restored its configuration", guildName, guildID)
+}
+
+func (b *Bot) purgeGuild(guildID string) {
+	if err := b.Repo.PurgeGuild(guildID); err != nil {
+		log.Printf("Error deleting data for guild %s: %v", guildID, err)
+		return
+	}
+	log.Printf("Successfully cleaned up data for guild %s", guildID)
+}
+
+// purgeExpiredGuilds deletes the data of guilds orphaned longer than the retention period.
+func (b *Bot) purgeExpiredGuilds(now time.Time) {
+	orphans, err := b.Repo.GetOrphanedGuildsBefore(now.Add(-guildRetention()).Unix())
+	if err != nil {
+		log.Printf("Error fetching orphaned guilds: %v", err)
+		return
+	}
+
+	for _, orphan := range orphans {
+		// The bot may have rejoined while the guild create event was missed.
+		if _, err := b.Session.State.Guild(orphan.GuildID); err == nil {
+			continue
+		}
+		b.purgeGuild(orphan.GuildID)
+	}
+}
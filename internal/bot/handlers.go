@@ -5,115 +5,52 @@ import (
 	"fmt"
 	"log"
 	"regexp"
-	"sort"
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/fvckgrimm/discord-fansly-notify/internal/config"
-	"github.com/fvckgrimm/discord-fansly-notify/internal/database"
 	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
 )
 
-var (
-	tokenRegex     = regexp.MustCompile(`[A-Za-z0-9]{40,}`)
-	fanslyURLRegex = regexp.MustCompile(`(?:https?://)?(?:www\.)?(?:fans\.ly|fansly\.com)/([^/\s]+)(?:/.*)?`)
-)
+var tokenRegex = regexp.MustCompile(`[A-Za-z0-9]{40,}`)
 
 func (b *Bot) ready(s *discordgo.Session, event *discordgo.Ready) {
 	log.Println("Bot is ready")
 	b.registerCommands()
 	b.updateBotStatus()
+	go b.cleanupScheduledEvents()
 }
 
 func (b *Bot) isBotOwner(i *discordgo.InteractionCreate) bool {
 	if config.BotOwnerID == "" {
 		return false // Can't be the owner if the ID isn't configured
 	}
-	return i.Member.User.ID == config.BotOwnerID
+	return interactionUserID(i) == config.BotOwnerID
 }
 
 func (b *Bot) interactionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	switch i.Type {
 	case discordgo.InteractionApplicationCommand:
-		// First, handle owner-only command checks
-		switch i.ApplicationCommandData().Name {
-		case "leave", "servers":
-			if !b.isBotOwner(i) {
-				b.respondToInteraction(s, i, "This command is for the bot owner only.", true)
-				return
-			}
-		}
-
-		// Second, handle general permission checks for non-owners
-		if !b.isBotOwner(i) && !b.hasAdminOrModPermissions(s, i) {
-			username := "User"
-			if i.User != nil {
-				username = i.User.Username
-			} else if i.Member != nil && i.Member.User != nil {
-				username = i.Member.User.Username
-			}
-			b.respondToInteraction(s, i, "You do not have permission to use this command.", true)
-			log.Printf("Permission denied for user %s", username)
-			return
-		}
-
-		// Finally, handle the command logic
-		switch i.ApplicationCommandData().Name {
-		case "add":
-			b.handleAddCommand(s, i)
-		case "remove":
-			b.handleRemoveCommand(s, i)
-		case "list":
-			b.handleListCommand(s, i)
-		case "setliveimage":
-			b.handleSetLiveImageCommand(s, i)
-		case "toggle":
-			b.handleToggleCommand(s, i)
-		case "setchannel":
-			b.handleSetChannelCommand(s, i)
-		case "setpostmention":
-			b.handleSetPostMentionCommand(s, i)
-		case "setlivemention":
-			b.handleSetLiveMentionCommand(s, i)
-		case "servers":
-			b.handleServersCommand(s, i)
-		case "leave":
-			b.handleLeaveCommand(s, i)
-		}
+		b.commands.dispatch(s, i)
 
 	case discordgo.InteractionMessageComponent:
-		// All button clicks and other components fall here.
-		// The specific handler for pagination is registered in `pagination.go`
-		// and will pick up the event. We don't need to do anything else here.
-		// This case prevents the code from panicking and allows the event to be processed correctly.
-	}
-}
-
-func extractUsernameFromURL(input string) string {
-	matches := fanslyURLRegex.FindStringSubmatch(input)
-	if len(matches) > 1 {
-		return matches[1]
-	}
+		b.handleComponentInteraction(s, i)
 
-	if len(input) > 0 && input[0] == '@' {
-		return input[1:]
+	case discordgo.InteractionModalSubmit:
+		b.handleModalSubmit(s, i)
 	}
-
-	return input
 }
 
-func (b *Bot) handleAddCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	options := i.ApplicationCommandData().Options
-	rawUsername := options[0].StringValue()
-
-	username := extractUsernameFromURL(rawUsername)
+func (b *Bot) handleAddCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	username := opts.String("username")
 
 	// Check if the limit is enabled (a value > 0)
 	if config.MaxMonitoredUsersPerGuild > 0 {
 		count, err := b.Repo.CountMonitoredUsersForGuild(i.GuildID)
 		if err != nil {
 			log.Printf("Error checking guild limit for guild %s: %v", i.GuildID, err)
-			b.respondToInteraction(s, i, "An error occurred while checking the server's limit. Please try again later.", true)
+			b.editInteractionResponse(s, i, "An error occurred while checking the server's limit. Please try again later.")
 			return
 		}
 
@@ -121,34 +58,34 @@ func (b *Bot) handleAddCommand(s *discordgo.Session, i *discordgo.InteractionCre
 			existingUser, _ := b.Repo.GetMonitoredUserByUsername(i.GuildID, username)
 			if existingUser == nil {
 				message := fmt.Sprintf("This server has reached its limit of %d monitored users. To add another, you must first remove one using `/remove`.", config.MaxMonitoredUsersPerGuild)
-				b.respondToInteraction(s, i, message, true)
+				b.editInteractionResponse(s, i, message)
 				return
 			}
 		}
 	}
 
 	if tokenRegex.MatchString(username) {
-		b.respondToInteraction(s, i, "Error: Username appears to contain a token. Please provide a valid username.", true)
-		return
-	}
-
-	// Defer the response to prevent a timeout. The final response will be public.
-	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
-	})
-	if err != nil {
-		log.Printf("Error deferring interaction: %v", err)
+		b.editInteractionResponse(s, i, "Error: Username appears to contain a token. Please provide a valid username.")
 		return
 	}
 
 	// Run all long-running tasks in a goroutine so the handler returns immediately.
 	go func() {
-		channel := options[1].ChannelValue(s)
 		var mentionRole string
-		if len(options) > 2 {
-			if role := options[2].RoleValue(s, i.GuildID); role != nil {
-				mentionRole = role.ID
-			}
+		if role := opts.Role(s, i.GuildID, "mention_role"); role != nil {
+			mentionRole = role.ID
+		}
+
+		channel := opts.Channel(s, "channel")
+		channelWarning, deliverable := b.validateNotificationChannel(channel, models.MonitoredUser{
+			GuildID: i.GuildID,
+		}, mentionRole)
+		if !deliverable {
+			b.editInteractionResponse(s, i, "Error: "+channelWarning)
+			return
+		}
+		if channelWarning != "" {
+			channelWarning = "\n⚠️ " + channelWarning
 		}
 
 		if config.LogChannelID != "" {
@@ -191,40 +128,10 @@ func (b *Bot) handleAddCommand(s *discordgo.Session, i *discordgo.InteractionCre
 			return
 		}
 
-		var avatarLocation string
-		if len(accountInfo.Avatar.Variants) > 0 && len(accountInfo.Avatar.Variants[0].Locations) > 0 {
-			avatarLocation = accountInfo.Avatar.Variants[0].Locations[0].Location
-		} else {
-			log.Printf("Warning: No avatar found for user %s", username)
-		}
-
-		timelinePosts, timelineErr := b.APIClient.GetTimelinePost(accountInfo.ID)
-		timelineAccessible := timelineErr == nil && len(timelinePosts) >= 0
-
-		if !timelineAccessible {
-			// Try to follow the account to gain access
-			if myAccount, err := b.APIClient.GetMyAccountInfo(); err == nil && myAccount.ID != "" {
-				if following, err := b.APIClient.GetFollowing(myAccount.ID); err == nil {
-					isFollowing := false
-					for _, f := range following {
-						if f.AccountID == accountInfo.ID {
-							isFollowing = true
-							break
-						}
-					}
-					if !isFollowing {
-						if followErr := b.APIClient.FollowAccount(accountInfo.ID); followErr != nil {
-							log.Printf("Note: Could not automatically follow %s: %v", username, followErr)
-						}
-					}
-				}
-			}
-			timelinePosts, timelineErr = b.APIClient.GetTimelinePost(accountInfo.ID)
-			timelineAccessible = timelineErr == nil
-		}
+		timelineAccessible := b.ensureTimelineAccess(accountInfo.ID, username, nil)
 
 		if !timelineAccessible {
-			b.editInteractionResponse(s, i, fmt.Sprintf("Cannot access timeline for **%s**. A confirmation message has been sent below.", username))
+			b.editInteractionResponse(s, i, fmt.Sprintf("Cannot access timeline for **%s**. A confirmation message has been sent below.%s", username, channelWarning))
 
 			confirmMsgContent := fmt.Sprintf("%s, do you want to add **%s** for **live notifications only**? React with ✅ to confirm or ❌ to cancel.", i.Member.Mention(), username)
 			msg, err := s.ChannelMessageSend(i.ChannelID, confirmMsgContent)
@@ -255,12 +162,8 @@ func (b *Bot) handleAddCommand(s *discordgo.Session, i *discordgo.InteractionCre
 			select {
 			case reaction := <-reactionChan:
 				if reaction == "✅" {
-					user := &models.MonitoredUser{
-						GuildID: i.GuildID, UserID: accountInfo.ID, Username: username, NotificationChannel: channel.ID, PostNotificationChannel: channel.ID,
-						LiveNotificationChannel: channel.ID, LastPostID: "", LastStreamStart: 0, MentionRole: mentionRole, AvatarLocation: avatarLocation,
-						AvatarLocationUpdatedAt: time.Now().Unix(), LiveImageURL: "", PostsEnabled: false, LiveEnabled: true, LiveMentionRole: mentionRole, PostMentionRole: mentionRole,
-					}
-					if err := database.NewRepository().AddOrUpdateMonitoredUser(user); err != nil {
+					user := newMonitoredUser(i.GuildID, accountInfo, username, channel.ID, mentionRole, false)
+					if err := b.Repo.AddOrUpdateMonitoredUser(user); err != nil {
 						s.ChannelMessageEdit(i.ChannelID, msg.ID, fmt.Sprintf("Error adding user: %v", err))
 					} else {
 						s.ChannelMessageEdit(i.ChannelID, msg.ID, fmt.Sprintf("✅ Added **%s** for live notifications only.", username))
@@ -275,70 +178,36 @@ func (b *Bot) handleAddCommand(s *discordgo.Session, i *discordgo.InteractionCre
 			return
 		}
 
-		repo := database.NewRepository()
-		user := &models.MonitoredUser{
-			GuildID: i.GuildID, UserID: accountInfo.ID, Username: username, NotificationChannel: channel.ID, PostNotificationChannel: channel.ID,
-			LiveNotificationChannel: channel.ID, LastPostID: "", LastStreamStart: 0, MentionRole: mentionRole, AvatarLocation: avatarLocation,
-			AvatarLocationUpdatedAt: time.Now().Unix(), LiveImageURL: "", PostsEnabled: true, LiveEnabled: true, LiveMentionRole: mentionRole, PostMentionRole: mentionRole,
-		}
+		user := newMonitoredUser(i.GuildID, accountInfo, username, channel.ID, mentionRole, true)
 
-		err = repo.AddOrUpdateMonitoredUser(user)
+		err = b.Repo.AddOrUpdateMonitoredUser(user)
 		if err != nil {
 			b.editInteractionResponse(s, i, fmt.Sprintf("Error storing user in database: %v", err))
 			return
 		}
 
-		b.editInteractionResponse(s, i, fmt.Sprintf("Successfully added **%s** to the monitoring list for all notifications.", username))
+		b.editInteractionResponse(s, i, fmt.Sprintf("Successfully added **%s** to the monitoring list for all notifications.%s", username, channelWarning))
 	}()
 }
 
-func (b *Bot) handleServersCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
-	})
-	if err != nil {
-		log.Printf("Error deferring interaction: %v", err)
-		return
-	}
-
-	guilds := b.Session.State.Guilds
-	if len(guilds) == 0 {
+func (b *Bot) handleServersCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	if len(b.Session.State.Guilds) == 0 {
 		b.editInteractionResponse(s, i, "The bot is not currently in any servers.")
 		return
 	}
 
-	// Sort guilds by name for a cleaner list
-	sort.Slice(guilds, func(i, j int) bool {
-		return guilds[i].Name < guilds[j].Name
-	})
-
-	var serverDetails []string
-	for _, guild := range guilds {
-		line := fmt.Sprintf("**%s**\n  `ID:` %s\n  `Members:` %d", guild.Name, guild.ID, guild.MemberCount)
-		serverDetails = append(serverDetails, line)
-	}
-
 	requestedPage := 1
-	if len(i.ApplicationCommandData().Options) > 0 {
-		requestedPage = int(i.ApplicationCommandData().Options[0].IntValue())
-		requestedPage = max(1, requestedPage)
+	if opts.Has("page") {
+		requestedPage = max(1, int(opts.Int("page")))
 	}
 
 	// We can reuse the existing pagination logic!
-	b.sendPaginatedList(s, i, serverDetails, requestedPage)
+	b.sendPaginatedList(s, i, listServers, "", requestedPage, "all")
 }
 
 // New handler for the /leave command
-func (b *Bot) handleLeaveCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
-	})
-	if err != nil {
-		log.Printf("Error deferring interaction: %v", err)
-		return
-	}
-
-	identifier := i.ApplicationCommandData().Options[0].StringValue()
+func (b *Bot) handleLeaveCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	identifier := opts.String("server")
 	var targetGuild *discordgo.Guild
 
 	// Search for the guild by ID or name
@@ -355,7 +224,7 @@ func (b *Bot) handleLeaveCommand(s *discordgo.Session, i *discordgo.InteractionC
 	}
 
 	// Leave the guild
-	err = s.GuildLeave(targetGuild.ID)
+	err := s.GuildLeave(targetGuild.ID)
 	if err != nil {
 		log.Printf("Failed to leave guild %s (%s): %v", targetGuild.Name, targetGuild.ID, err)
 		b.editInteractionResponse(s, i, fmt.Sprintf("An error occurred while trying to leave **%s**.", targetGuild.Name))
@@ -366,19 +235,18 @@ func (b *Bot) handleLeaveCommand(s *discordgo.Session, i *discordgo.InteractionC
 	b.editInteractionResponse(s, i, fmt.Sprintf("✅ Successfully left **%s**.", targetGuild.Name))
 }
 
-func (b *Bot) handleRemoveCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
-	})
-	if err != nil {
-		log.Printf("Error deferring interaction: %v", err)
-		return
-	}
+func (b *Bot) handleRemoveCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	username := opts.String("username")
 
-	username := i.ApplicationCommandData().Options[0].StringValue()
+	user, err := b.Repo.GetMonitoredUserByUsername(i.GuildID, username)
+	if err == nil && user != nil {
+		b.deleteNotifyRole(user)
+		if err := b.Repo.DeleteUserSubscriptionsForCreator(i.GuildID, user.UserID); err != nil {
+			log.Printf("Error removing DM subscriptions for %s in guild %s: %v", username, i.GuildID, err)
+		}
+	}
 
-	repo := database.NewRepository()
-	err = repo.DeleteMonitoredUserByUsername(i.GuildID, username)
+	err = b.Repo.DeleteMonitoredUserByUsername(i.GuildID, username)
 	if err != nil {
 		b.editInteractionResponse(s, i, fmt.Sprintf("Error removing user: %v", err))
 		return
@@ -402,71 +270,87 @@ func (b *Bot) respondToInteraction(s *discordgo.Session, i *discordgo.Interactio
 	})
 }
 
-func (b *Bot) handleListCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
-	})
-	if err != nil {
-		log.Printf("Error deferring interaction: %v", err)
-		return
+func (b *Bot) handleListCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	requestedPage := 1
+	if opts.Has("page") {
+		requestedPage = max(1, int(opts.Int("page")))
 	}
 
-	requestedPage := 1
-	if len(i.ApplicationCommandData().Options) > 0 {
-		requestedPage = int(i.ApplicationCommandData().Options[0].IntValue())
-		requestedPage = max(1, requestedPage)
+	filter := "all"
+	if opts.Has("filter") {
+		filter = opts.String("filter")
 	}
 
-	repo := database.NewRepository()
-	users, err := repo.GetMonitoredUsersForGuild(i.GuildID)
+	count, err := b.Repo.CountMonitoredUsersForGuild(i.GuildID)
 	if err != nil {
 		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching monitored users: %v", err))
 		return
 	}
 
-	if len(users) == 0 {
+	if count == 0 {
 		b.editInteractionResponse(s, i, "No models are currently being monitored.")
 		return
 	}
 
-	var monitoredUsers []string
-	for _, user := range users {
-		postChannelInfo := fmt.Sprintf("<#%s>", user.PostNotificationChannel)
-		liveChannelInfo := fmt.Sprintf("<#%s>", user.LiveNotificationChannel)
-		roleInfoPost := getRoleName(user.PostMentionRole)
-		roleInfoLive := getRoleName(user.LiveMentionRole)
+	b.sendPaginatedList(s, i, listCreators, i.GuildID, requestedPage, filter)
+}
 
-		postStatus := "✅ Enabled"
-		if !user.PostsEnabled {
-			postStatus = "❌ Disabled"
+// formatMonitoredUser describes a subscription for /list.
+func (b *Bot) formatMonitoredUser(user models.MonitoredUser) string {
+	postChannelInfo := fmt.Sprintf("<#%s>", user.PostNotificationChannel)
+	liveChannelInfo := fmt.Sprintf("<#%s>", user.LiveNotificationChannel)
+	roleInfoPost := getRoleName(user.PostMentionRole)
+	roleInfoLive := getRoleName(user.LiveMentionRole)
+
+	postStatus := "✅ Enabled"
+	if !user.PostsEnabled {
+		postStatus = "❌ Disabled"
+	} else if user.PostsSuspended {
+		postStatus = "⏸️ Paused, no timeline access"
+	}
+	liveStatus := "✅ Enabled"
+	if !user.LiveEnabled {
+		liveStatus = "❌ Disabled"
+	}
+
+	userInfo := fmt.Sprintf("- **%s**\n  • Posts: %s (in %s | Role: %s)\n  • Live: %s (in %s | Role: %s)",
+		user.Username,
+		postStatus, postChannelInfo, roleInfoPost,
+		liveStatus, liveChannelInfo, roleInfoLive,
+	)
+	if mode := user.DeliveryMode(); mode != models.DeliveryInstant {
+		userInfo += fmt.Sprintf("\n  • Delivery: %s digest", mode)
+	}
+	if user.WebhookDelivery {
+		userInfo += "\n  • Posting as the creator via webhook"
+	}
+	if user.NotifyButtons {
+		userInfo += "\n  • Notify me buttons"
+		if user.NotifyRoleID != "" {
+			userInfo += fmt.Sprintf(" (<@&%s>)", user.NotifyRoleID)
 		}
-		liveStatus := "✅ Enabled"
-		if !user.LiveEnabled {
-			liveStatus = "❌ Disabled"
+	}
+	if user.AutoCrosspost {
+		userInfo += "\n  • Auto-publish in announcement channels"
+	}
+	if user.ThreadPosts || user.ThreadLive {
+		var threadKinds []string
+		if user.ThreadPosts {
+			threadKinds = append(threadKinds, "posts")
 		}
-
-		userInfo := fmt.Sprintf("- **%s**\n  • Posts: %s (in %s | Role: %s)\n  • Live: %s (in %s | Role: %s)",
-			user.Username,
-			postStatus, postChannelInfo, roleInfoPost,
-			liveStatus, liveChannelInfo, roleInfoLive,
-		)
-		monitoredUsers = append(monitoredUsers, userInfo)
+		if user.ThreadLive {
+			threadKinds = append(threadKinds, "live")
+		}
+		userInfo += fmt.Sprintf("\n  • Threads: %s", strings.Join(threadKinds, ", "))
 	}
-
-	b.sendPaginatedList(s, i, monitoredUsers, requestedPage)
-}
-
-func (b *Bot) handleSetLiveImageCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
-	})
-	if err != nil {
-		log.Printf("Error acknowledging interaction: %v", err)
-		return
+	if isMuted(user, b.Clock.Now()) {
+		userInfo += fmt.Sprintf("\n  • 🔕 Muted until <t:%d:f>", user.MutedUntil)
 	}
+	return userInfo
+}
 
-	options := i.ApplicationCommandData().Options
-	username := options[0].StringValue()
+func (b *Bot) handleSetLiveImageCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	username := opts.String("username")
 
 	var imageURL string
 	if attachments := i.ApplicationCommandData().Resolved.Attachments; len(attachments) > 0 {
@@ -481,8 +365,7 @@ func (b *Bot) handleSetLiveImageCommand(s *discordgo.Session, i *discordgo.Inter
 		return
 	}
 
-	repo := database.NewRepository()
-	err = repo.UpdateLiveImageURL(i.GuildID, username, imageURL)
+	err := b.Repo.UpdateLiveImageURL(i.GuildID, username, imageURL)
 	if err != nil {
 		log.Printf("Error updating live image URL: %v", err)
 		b.editInteractionResponse(s, i, fmt.Sprintf("An error occurred while setting the live image: %v", err))
@@ -492,35 +375,25 @@ func (b *Bot) handleSetLiveImageCommand(s *discordgo.Session, i *discordgo.Inter
 	b.editInteractionResponse(s, i, fmt.Sprintf("Live image for **%s** has been set successfully.", username))
 }
 
-func (b *Bot) handleToggleCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
-	})
-	if err != nil {
-		log.Printf("Error deferring interaction: %v", err)
-		return
-	}
+func (b *Bot) handleToggleCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	username := opts.String("username")
+	notifiType := opts.String("type")
+	enabled := opts.Bool("enabled")
 
-	options := i.ApplicationCommandData().Options
-	username := options[0].StringValue()
-	notifiType := options[1].StringValue()
-	enabled := options[2].BoolValue()
-
-	repo := database.NewRepository()
 	var updateErr error
 
 	switch notifiType {
 	case "posts":
 		if enabled {
-			updateErr = repo.EnablePostsByUsername(i.GuildID, username)
+			updateErr = b.Repo.EnablePostsByUsername(i.GuildID, username)
 		} else {
-			updateErr = repo.DisablePostsByUsername(i.GuildID, username)
+			updateErr = b.Repo.DisablePostsByUsername(i.GuildID, username)
 		}
 	case "live":
 		if enabled {
-			updateErr = repo.EnableLiveByUsername(i.GuildID, username)
+			updateErr = b.Repo.EnableLiveByUsername(i.GuildID, username)
 		} else {
-			updateErr = repo.DisableLiveByUsername(i.GuildID, username)
+			updateErr = b.Repo.DisableLiveByUsername(i.GuildID, username)
 		}
 	default:
 		b.editInteractionResponse(s, i, "Invalid notification type selected.")
@@ -540,28 +413,37 @@ func (b *Bot) handleToggleCommand(s *discordgo.Session, i *discordgo.Interaction
 	b.editInteractionResponse(s, i, fmt.Sprintf("`%s` notifications have been **%s** for **%s**.", notifiType, status, username))
 }
 
-func (b *Bot) handleSetChannelCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
-	})
-	if err != nil {
-		log.Printf("Error deferring interaction: %v", err)
+func (b *Bot) handleSetChannelCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	username := opts.String("username")
+	notifType := opts.String("type")
+	channel := opts.Channel(s, "channel")
+
+	user, err := b.Repo.GetMonitoredUserByUsername(i.GuildID, username)
+	if err != nil || user == nil {
+		b.editInteractionResponse(s, i, "Error updating channel: user not found")
 		return
 	}
 
-	options := i.ApplicationCommandData().Options
-	username := options[0].StringValue()
-	notifType := options[1].StringValue()
-	channel := options[2].ChannelValue(s)
+	mentionRole := user.PostMentionRole
+	if notifType == "live" {
+		mentionRole = user.LiveMentionRole
+	}
+	channelWarning, deliverable := b.validateNotificationChannel(channel, *user, mentionRole)
+	if !deliverable {
+		b.editInteractionResponse(s, i, "Error: "+channelWarning)
+		return
+	}
+	if channelWarning != "" {
+		channelWarning = "\n⚠️ " + channelWarning
+	}
 
-	repo := database.NewRepository()
 	var updateErr error
 
 	switch notifType {
 	case "posts":
-		updateErr = repo.UpdatePostChannel(i.GuildID, username, channel.ID)
+		updateErr = b.Repo.UpdatePostChannel(i.GuildID, username, channel.ID)
 	case "live":
-		updateErr = repo.UpdateLiveChannel(i.GuildID, username, channel.ID)
+		updateErr = b.Repo.UpdateLiveChannel(i.GuildID, username, channel.ID)
 	default:
 		b.editInteractionResponse(s, i, "Invalid notification type.")
 		return
@@ -572,81 +454,289 @@ func (b *Bot) handleSetChannelCommand(s *discordgo.Session, i *discordgo.Interac
 		return
 	}
 
-	b.editInteractionResponse(s, i, fmt.Sprintf("Successfully set the %s notification channel for **%s** to %s.", notifType, username, channel.Mention()))
+	b.editInteractionResponse(s, i, fmt.Sprintf("Successfully set the %s notification channel for **%s** to %s.%s", notifType, username, channel.Mention(), channelWarning))
 }
 
-func (b *Bot) handleSetPostMentionCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
-	})
+func (b *Bot) handleSetPostMentionCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	b.updateMentionRole(s, i, opts.String("username"), "posts", opts.Role(s, i.GuildID, "role"))
+}
+
+func (b *Bot) handleSetLiveMentionCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	b.updateMentionRole(s, i, opts.String("username"), "live", opts.Role(s, i.GuildID, "role"))
+}
+
+func (b *Bot) handleNotifyMentionCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	b.updateMentionRole(s, i, opts.String("username"), opts.String("type"), opts.Role(s, i.GuildID, "role"))
+}
+
+// updateMentionRole sets or, when role is nil, clears the role mentioned for a
+// creator's post or live notifications.
+func (b *Bot) updateMentionRole(s *discordgo.Session, i *discordgo.InteractionCreate, username, notifType string, role *discordgo.Role) {
+	var roleID string
+	if role != nil {
+		roleID = role.ID
+	}
+
+	var err error
+	var label string
+
+	switch notifType {
+	case "posts":
+		label = "Post"
+		err = b.Repo.UpdatePostMentionRole(i.GuildID, username, roleID)
+	case "live":
+		label = "Live"
+		err = b.Repo.UpdateLiveMentionRole(i.GuildID, username, roleID)
+	default:
+		b.editInteractionResponse(s, i, "Invalid notification type.")
+		return
+	}
+
 	if err != nil {
-		log.Printf("Error deferring interaction: %v", err)
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error updating %s mention role: %v", strings.ToLower(label), err))
 		return
 	}
 
-	options := i.ApplicationCommandData().Options
-	username := options[0].StringValue()
-	var roleID string
-	var roleMention string
+	message := fmt.Sprintf("%s mention role for **%s** has been cleared.", label, username)
+	if roleID != "" {
+		message = fmt.Sprintf("%s mention role for **%s** set to %s.", label, username, role.Mention())
+	}
+	b.editInteractionResponse(s, i, message)
+}
 
-	if len(options) > 1 {
-		role := options[1].RoleValue(s, i.GuildID)
-		if role != nil {
-			roleID = role.ID
-			roleMention = role.Mention()
-		}
+func (b *Bot) handleSetDeliveryCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	username := opts.String("username")
+	mode := opts.String("mode")
+
+	switch mode {
+	case models.DeliveryInstant, models.DeliveryHourly, models.DeliveryDaily:
+	default:
+		b.editInteractionResponse(s, i, "Invalid delivery mode selected.")
+		return
 	}
 
-	repo := database.NewRepository()
-	err = repo.UpdatePostMentionRole(i.GuildID, username, roleID)
+	err := b.Repo.UpdateDeliveryModeByUsername(i.GuildID, username, mode)
 	if err != nil {
-		b.editInteractionResponse(s, i, fmt.Sprintf("Error updating post mention role: %v", err))
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error updating delivery mode: %v", err))
 		return
 	}
 
-	message := fmt.Sprintf("Post mention role for **%s** has been cleared.", username)
-	if roleID != "" {
-		message = fmt.Sprintf("Post mention role for **%s** set to %s.", username, roleMention)
+	message := fmt.Sprintf("Post notifications for **%s** will be sent instantly.", username)
+	if mode != models.DeliveryInstant {
+		message = fmt.Sprintf("Post notifications for **%s** will be collected into an **%s** digest. Live notifications are still sent instantly.", username, mode)
 	}
 	b.editInteractionResponse(s, i, message)
 }
 
-func (b *Bot) handleSetLiveMentionCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
-	})
+func (b *Bot) handleSetTimezoneCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	timezone := opts.String("timezone")
+
+	if _, err := time.LoadLocation(timezone); err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Unknown timezone `%s`. Use an IANA name such as `Europe/Berlin` or `America/New_York`.", timezone))
+		return
+	}
+
+	settings, err := b.Repo.GetGuildSettings(i.GuildID)
 	if err != nil {
-		log.Printf("Error deferring interaction: %v", err)
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching server settings: %v", err))
 		return
 	}
 
-	options := i.ApplicationCommandData().Options
-	username := options[0].StringValue()
-	var roleID string
-	var roleMention string
+	settings.Timezone = timezone
+	if opts.Has("digest_hour") {
+		settings.DigestHour = int(opts.Int("digest_hour"))
+	}
 
-	if len(options) > 1 {
-		role := options[1].RoleValue(s, i.GuildID)
-		if role != nil {
-			roleID = role.ID
-			roleMention = role.Mention()
-		}
+	err = b.Repo.SaveGuildSettings(settings)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error saving server settings: %v", err))
+		return
 	}
 
-	repo := database.NewRepository()
-	err = repo.UpdateLiveMentionRole(i.GuildID, username, roleID)
+	b.editInteractionResponse(s, i, fmt.Sprintf("Server timezone set to `%s`. Daily digests are sent at %02d:00.", settings.Timezone, settings.DigestHour))
+}
+
+func (b *Bot) handleSetIdentityCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	username := opts.String("username")
+	useWebhook := opts.String("identity") == "creator"
+
+	err := b.Repo.UpdateWebhookDeliveryByUsername(i.GuildID, username, useWebhook)
 	if err != nil {
-		b.editInteractionResponse(s, i, fmt.Sprintf("Error updating live mention role: %v", err))
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error updating notification identity: %v", err))
 		return
 	}
 
-	message := fmt.Sprintf("Live mention role for **%s** has been cleared.", username)
-	if roleID != "" {
-		message = fmt.Sprintf("Live mention role for **%s** set to %s.", username, roleMention)
+	message := fmt.Sprintf("Notifications for **%s** will be posted by the bot.", username)
+	if useWebhook {
+		message = fmt.Sprintf("Notifications for **%s** will be posted with the creator's name and avatar. The bot needs the **Manage Webhooks** permission in the notification channels; otherwise it falls back to posting as itself.", username)
 	}
 	b.editInteractionResponse(s, i, message)
 }
 
+func (b *Bot) handleSetThreadsCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	username := opts.String("username")
+	notifType := opts.String("type")
+	enabled := opts.Bool("enabled")
+
+	var updateErr error
+
+	switch notifType {
+	case "posts":
+		updateErr = b.Repo.UpdatePostThreadsByUsername(i.GuildID, username, enabled)
+	case "live":
+		updateErr = b.Repo.UpdateLiveThreadsByUsername(i.GuildID, username, enabled)
+	default:
+		b.editInteractionResponse(s, i, "Invalid notification type.")
+		return
+	}
+
+	if updateErr != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error updating thread setting: %v", updateErr))
+		return
+	}
+
+	status := "will start a discussion thread"
+	if !enabled {
+		status = "will no longer start a thread"
+	}
+	b.editInteractionResponse(s, i, fmt.Sprintf("`%s` notifications for **%s** %s. Forum channels always get one post per notification.", notifType, username, status))
+}
+
+func (b *Bot) handleSetCrosspostCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	username := opts.String("username")
+	enabled := opts.Bool("enabled")
+
+	if err := b.Repo.UpdateAutoCrosspostByUsername(i.GuildID, username, enabled); err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error updating crosspost setting: %v", err))
+		return
+	}
+
+	status := "will be published to following servers"
+	if !enabled {
+		status = "will no longer be published automatically"
+	}
+	b.editInteractionResponse(s, i, fmt.Sprintf("Notifications for **%s** in announcement channels %s.", username, status))
+}
+
+func (b *Bot) handleSetButtonsCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	username := opts.String("username")
+	enabled := opts.Bool("enabled")
+
+	user, err := b.Repo.GetMonitoredUserByUsername(i.GuildID, username)
+	if err != nil || user == nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error: **%s** is not being monitored in this server.", username))
+		return
+	}
+
+	if enabled {
+		// Create the role up front so a missing Manage Roles permission shows up now
+		// instead of on the first click.
+		if _, err := b.ensureNotifyRole(user, false); err != nil {
+			log.Printf("Error creating notify role for %s in guild %s: %v", username, i.GuildID, err)
+			b.editInteractionResponse(s, i, "Error: I couldn't create the notification role. Please give me the Manage Roles permission.")
+			return
+		}
+	}
+
+	if err := b.Repo.UpdateNotifyButtonsByUsername(i.GuildID, username, enabled); err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error updating buttons: %v", err))
+		return
+	}
+
+	if !enabled && user.NotifyRoleID == "" {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Notifications for **%s** will no longer have buttons.", username))
+		return
+	}
+	if !enabled {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Notifications for **%s** will no longer have buttons. The <@&%s> role is kept until the creator is removed.", username, user.NotifyRoleID))
+		return
+	}
+	b.editInteractionResponse(s, i, fmt.Sprintf("Notifications for **%s** now have a 🔔 button. Members who click it get <@&%s>, which is pinged on every notification.", username, user.NotifyRoleID))
+}
+
+func (b *Bot) handleQuietHoursCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	settings, err := b.Repo.GetGuildSettings(i.GuildID)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching server settings: %v", err))
+		return
+	}
+
+	for _, option := range opts {
+		switch option.Name {
+		case "enabled":
+			settings.QuietHoursEnabled = option.BoolValue()
+		case "start":
+			settings.QuietStart, err = parseClock(option.StringValue())
+		case "end":
+			settings.QuietEnd, err = parseClock(option.StringValue())
+		case "mode":
+			settings.QuietMode = option.StringValue()
+		}
+		if err != nil {
+			b.editInteractionResponse(s, i, fmt.Sprintf("Error: %v", err))
+			return
+		}
+	}
+	if settings.QuietMode == "" {
+		settings.QuietMode = models.QuietModeSuppress
+	}
+
+	if settings.QuietHoursEnabled && settings.QuietStart == settings.QuietEnd {
+		b.editInteractionResponse(s, i, "Quiet hours need a `start` and `end` time that differ, e.g. `01:00` and `08:00`.")
+		return
+	}
+
+	err = b.Repo.SaveGuildSettings(settings)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error saving server settings: %v", err))
+		return
+	}
+
+	if !settings.QuietHoursEnabled {
+		b.editInteractionResponse(s, i, "Quiet hours have been **disabled**.")
+		return
+	}
+
+	behaviour := "role mentions will be suppressed"
+	if settings.QuietMode == models.QuietModeQueue {
+		behaviour = "notifications will be queued until the window ends"
+	}
+	b.editInteractionResponse(s, i, fmt.Sprintf("Quiet hours set to **%s–%s** (`%s`); %s.",
+		formatClock(settings.QuietStart), formatClock(settings.QuietEnd), settings.Location(), behaviour))
+}
+
+func (b *Bot) handleMuteCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	username := opts.String("username")
+
+	duration, err := parseMuteDuration(opts.String("duration"))
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error: %v", err))
+		return
+	}
+
+	mutedUntil := b.Clock.Now().Add(duration).Unix()
+
+	err = b.Repo.UpdateMutedUntilByUsername(i.GuildID, username, mutedUntil)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error muting notifications: %v", err))
+		return
+	}
+
+	b.editInteractionResponse(s, i, fmt.Sprintf("🔕 Notifications for **%s** are muted until <t:%d:f>.", username, mutedUntil))
+}
+
+func (b *Bot) handleUnmuteCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	username := opts.String("username")
+
+	err := b.Repo.UpdateMutedUntilByUsername(i.GuildID, username, 0)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error unmuting notifications: %v", err))
+		return
+	}
+
+	b.editInteractionResponse(s, i, fmt.Sprintf("🔔 Notifications for **%s** are no longer muted.", username))
+}
+
 func getRoleName(roleID string) string {
 	if roleID == "" || roleID == "0" {
 		return "None"
@@ -0,0 +1,284 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fvckgrimm/discord-fansly-notify/api"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+// unhealthyErrorCount is how many failed checks in a row mark a creator as failing.
+const unhealthyErrorCount = 3
+
+// healthTracker keeps the latest check results per creator in memory. Workers
+// record into it and the results are persisted once per monitoring cycle so
+// /status survives restarts without a write per API call.
+type healthTracker struct {
+	mu       sync.Mutex
+	clock    Clock
+	creators map[string]*models.CreatorHealth
+	dirty    map[string]bool
+}
+
+func newHealthTracker(clock Clock) *healthTracker {
+	return &healthTracker{
+		clock:    clock,
+		creators: make(map[string]*models.CreatorHealth),
+		dirty:    make(map[string]bool),
+	}
+}
+
+// load seeds the tracker with persisted summaries.
+func (t *healthTracker) load(health []models.CreatorHealth) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for idx := range health {
+		t.creators[health[idx].CreatorID] = &health[idx]
+	}
+}
+
+// entry returns the creator's summary, creating it if needed. t.mu must be held.
+func (t *healthTracker) entry(creatorID string) *models.CreatorHealth {
+	h, ok := t.creators[creatorID]
+	if !ok {
+		h = &models.CreatorHealth{CreatorID: creatorID}
+		t.creators[creatorID] = h
+	}
+	t.dirty[creatorID] = true
+	return h
+}
+
+// recordCheck records the outcome of one monitoring check of a creator.
+func (t *healthTracker) recordCheck(creatorID string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.clock.Now().Unix()
+	h := t.entry(creatorID)
+	h.LastCheckAt = now
+	if err != nil {
+		h.ConsecutiveErrors++
+		h.LastError = err.Error()
+		h.LastErrorAt = now
+		return
+	}
+	h.ConsecutiveErrors = 0
+	h.LastSuccessAt = now
+}
+
+// recordTimeline records whether the creator's timeline could be read and,
+// when it could, the creation time of the newest post.
+func (t *healthTracker) recordTimeline(creatorID string, accessible bool, lastPostAt int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h := t.entry(creatorID)
+	h.TimelineCheckedAt = t.clock.Now().Unix()
+	h.TimelineAccessible = accessible
+	if lastPostAt > h.LastPostAt {
+		h.LastPostAt = lastPostAt
+	}
+}
+
+func (t *healthTracker) get(creatorID string) (models.CreatorHealth, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if h, ok := t.creators[creatorID]; ok {
+		return *h, true
+	}
+	return models.CreatorHealth{}, false
+}
+
+// takeDirty returns the summaries changed since the last call.
+func (t *healthTracker) takeDirty() []models.CreatorHealth {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	health := make([]models.CreatorHealth, 0, len(t.dirty))
+	for creatorID := range t.dirty {
+		health = append(health, *t.creators[creatorID])
+	}
+	t.dirty = make(map[string]bool)
+	return health
+}
+
+// prune forgets creators that are no longer monitored and returns their IDs.
+func (t *healthTracker) prune(monitored map[string]bool) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var removed []string
+	for creatorID := range t.creators {
+		if !monitored[creatorID] {
+			delete(t.creators, creatorID)
+			delete(t.dirty, creatorID)
+			removed = append(removed, creatorID)
+		}
+	}
+	return removed
+}
+
+func (b *Bot) loadCreatorHealth() {
+	health, err := b.Repo.GetAllCreatorHealth()
+	if err != nil {
+		log.Printf("Error loading creator health: %v", err)
+		return
+	}
+	b.health.load(health)
+}
+
+// persistCreatorHealth stores the results of the previous monitoring cycle and
+// drops the summaries of creators nobody monitors anymore, starting their
+// unfollow grace period.
+func (b *Bot) persistCreatorHealth(monitored map[string]bool) {
+	removed := b.health.prune(monitored)
+	for _, creatorID := range removed {
+		if err := b.Repo.DeleteCreatorHealth(creatorID); err != nil {
+			log.Printf("Error deleting health of creator %s: %v", creatorID, err)
+		}
+	}
+	b.markUnmonitoredCreators(removed)
+
+	if health := b.health.takeDirty(); len(health) > 0 {
+		if err := b.Repo.SaveCreatorHealth(health); err != nil {
+			log.Printf("Error saving creator health: %v", err)
+		}
+	}
+}
+
+// recordDeliveryResult keeps the last delivery error of a subscription for
+// /status and clears it after the next successful delivery.
+func (b *Bot) recordDeliveryResult(user models.MonitoredUser, err error) {
+	if err == nil && user.LastDeliveryError == "" {
+		return
+	}
+
+	message, at := "", int64(0)
+	if err != nil {
+		message, at = err.Error(), b.Clock.Now().Unix()
+	}
+	if err := b.Repo.UpdateDeliveryError(user.GuildID, user.UserID, message, at); err != nil {
+		log.Printf("Error recording delivery result for %s in guild %s: %v", user.Username, user.GuildID, err)
+	}
+}
+
+// isTimelineAccessError reports whether a timeline error means the bot's account may not read it.
+func isTimelineAccessError(err error) bool {
+	return errors.Is(err, api.ErrNoTimelineAccess)
+}
+
+// healthIcon summarises a subscription's state in one emoji.
+func healthIcon(user models.MonitoredUser, health models.CreatorHealth, checked bool) string {
+	switch {
+	case !checked:
+		return "⏳"
+	case health.ConsecutiveErrors >= unhealthyErrorCount:
+		return "❌"
+	case health.ConsecutiveErrors > 0, user.LastDeliveryError != "", user.PostsEnabled && user.PostsSuspended,
+		user.PostsEnabled && health.TimelineCheckedAt > 0 && !health.TimelineAccessible:
+		return "⚠️"
+	}
+	return "✅"
+}
+
+func formatTimestamp(unix int64) string {
+	if unix == 0 {
+		return "never"
+	}
+	return fmt.Sprintf("<t:%d:R>", unix)
+}
+
+// formatCreatorStatus describes the monitoring health of a subscription.
+func (b *Bot) formatCreatorStatus(user models.MonitoredUser) string {
+	health, checked := b.health.get(user.UserID)
+
+	lines := []string{fmt.Sprintf("%s **%s**", healthIcon(user, health, checked), user.Username)}
+	if !checked {
+		lines = append(lines, "  • Not checked yet")
+		return strings.Join(lines, "\n")
+	}
+
+	lines = append(lines, fmt.Sprintf("  • Last successful check: %s", formatTimestamp(health.LastSuccessAt)))
+
+	switch {
+	case !user.PostsEnabled:
+		lines = append(lines, "  • Posts: notifications disabled")
+	case user.PostsSuspended:
+		lines = append(lines, "  • Posts: ⏸️ paused, no timeline access; they resume once the bot's account can read it")
+	case health.TimelineCheckedAt == 0:
+		lines = append(lines, "  • Posts: timeline not read yet")
+	case !health.TimelineAccessible:
+		lines = append(lines, "  • Posts: ⚠️ no timeline access, follow or subscribe with the bot's account")
+	default:
+		lines = append(lines, fmt.Sprintf("  • Last post seen: %s", formatTimestamp(health.LastPostAt)))
+	}
+
+	if user.IsLive {
+		lines = append(lines, fmt.Sprintf("  • Live: 🔴 since %s", formatTimestamp(user.LastStreamStart)))
+	} else {
+		lines = append(lines, "  • Live: offline")
+	}
+
+	if health.ConsecutiveErrors > 0 {
+		lines = append(lines, fmt.Sprintf("  • API errors in a row: %d (last %s: `%s`)",
+			health.ConsecutiveErrors, formatTimestamp(health.LastErrorAt), truncateError(health.LastError)))
+	}
+	if user.LastDeliveryError != "" {
+		lines = append(lines, fmt.Sprintf("  • Last delivery error %s: `%s`",
+			formatTimestamp(user.LastDeliveryErrorAt), truncateError(user.LastDeliveryError)))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// truncateError keeps error messages from blowing up the embed.
+func truncateError(message string) string {
+	const maxLength = 150
+	message = strings.ReplaceAll(message, "`", "'")
+	if len(message) <= maxLength {
+		return message
+	}
+	return message[:maxLength-1] + "…"
+}
+
+func (b *Bot) handleStatusCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	if !opts.Has("username") {
+		count, err := b.Repo.CountMonitoredUsersForGuild(i.GuildID)
+		if err != nil {
+			b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching monitored users: %v", err))
+			return
+		}
+		if count == 0 {
+			b.editInteractionResponse(s, i, "No models are currently being monitored.")
+			return
+		}
+		b.sendPaginatedList(s, i, listStatus, i.GuildID, 1, "all")
+		return
+	}
+
+	username := opts.String("username")
+	user, err := b.Repo.GetMonitoredUserByUsername(i.GuildID, username)
+	if err != nil || user == nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("**%s** isn't monitored in this server.", username))
+		return
+	}
+
+	description := b.formatCreatorStatus(*user)
+	if aliases := b.formatAliases(user.UserID); aliases != "" {
+		description += fmt.Sprintf("\n  • Previously known as: %s", aliases)
+	}
+
+	embeds := []*discordgo.MessageEmbed{{
+		Title:       "Monitoring Status",
+		Description: description,
+		Color:       0x03b2f8,
+	}}
+	if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Embeds: &embeds}); err != nil {
+		log.Printf("Error editing interaction response for /status: %v", err)
+	}
+}
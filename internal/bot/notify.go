@@ -0,0 +1,140 @@
+package bot
+
+import (
+	"errors"
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+// Notification kinds. They are stored with queued notifications, so the
+// values must not change.
+const (
+	kindPost   = "post"
+	kindLive   = "live stream"
+	kindDigest = "digest"
+)
+
+// notification is a single message about to be delivered to a guild channel.
+type notification struct {
+	Kind    string // kindPost, kindLive or kindDigest
+	User    models.MonitoredUser
+	Channel string
+	Mention string
+	Embed   *discordgo.MessageEmbed
+}
+
+// deliverNotification applies the subscription's mute and the guild's quiet
+// hours before sending. It returns an error only when the notification could
+// be neither sent nor queued; muted notifications are dropped on purpose.
+func (b *Bot) deliverNotification(n notification) error {
+	now := b.Clock.Now()
+
+	if isMuted(n.User, now) {
+		log.Printf("Skipping %s notification for %s in guild %s: muted", n.Kind, n.User.Username, n.User.GuildID)
+		return nil
+	}
+
+	n.Mention = joinMentions(n.Mention, notifyRoleMention(n.User))
+
+	settings, err := b.Repo.GetGuildSettings(n.User.GuildID)
+	if err != nil {
+		log.Printf("Error fetching settings for guild %s, sending without quiet hours: %v", n.User.GuildID, err)
+	} else if inQuietHours(*settings, now) {
+		if settings.QuietMode == models.QuietModeQueue {
+			err := b.queueNotification(n, quietHoursEnd(*settings, now))
+			if err != nil {
+				log.Printf("Error queueing %s notification for %s in guild %s: %v", n.Kind, n.User.Username, n.User.GuildID, err)
+			}
+			return err
+		}
+		n.Mention = ""
+	}
+
+	return b.sendNotification(n)
+}
+
+func (b *Bot) sendNotification(n notification) error {
+	channel, err := b.channelInfo(n.Channel)
+	if err != nil {
+		b.logNotificationError(n.Kind, n.User, n.Channel, err)
+		b.recordDeliveryResult(n.User, err)
+		return err
+	}
+
+	msg, err := b.postNotification(n, channel)
+	b.recordDeliveryResult(n.User, err)
+	if err != nil {
+		b.logNotificationError(n.Kind, n.User, n.Channel, err)
+		return err
+	}
+
+	b.crosspostNotification(n, channel, msg)
+	b.startNotificationThread(n, channel, msg)
+	return nil
+}
+
+// postNotification sends the message to a text, news, thread or forum channel,
+// as the creator via webhook when enabled and as the bot otherwise.
+func (b *Bot) postNotification(n notification, channel *discordgo.Channel) (*discordgo.Message, error) {
+	if n.User.WebhookDelivery {
+		msg, err := b.sendViaWebhook(n, channel)
+		if err == nil {
+			return msg, nil
+		}
+		log.Printf("Webhook delivery failed for %s in guild %s, falling back to a bot message: %v", n.User.Username, n.User.GuildID, err)
+	}
+
+	message := &discordgo.MessageSend{
+		Content:    n.Mention,
+		Embed:      n.Embed,
+		Components: notificationComponents(n),
+	}
+
+	if channel.Type == discordgo.ChannelTypeGuildForum {
+		thread, err := b.Discord.ForumThreadStartComplex(channel.ID, &discordgo.ThreadStart{
+			Name:                notificationThreadName(n),
+			AutoArchiveDuration: threadArchiveMinutes,
+			AppliedTags:         b.forumCreatorTags(channel, n.User.Username),
+		}, message)
+		if err != nil {
+			return nil, err
+		}
+		// The starter message of a forum post shares the thread's ID.
+		return &discordgo.Message{ID: thread.ID, ChannelID: thread.ID}, nil
+	}
+
+	return b.Discord.ChannelMessageSendComplex(channel.ID, message)
+}
+
+// sendNotice posts a plain message from the bot in the channel the
+// subscription's post notifications go to.
+func (b *Bot) sendNotice(user models.MonitoredUser, kind, content string) {
+	channelID := user.PostNotificationChannel
+	if channelID == "" {
+		channelID = user.NotificationChannel
+	}
+
+	_, err := b.Discord.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{Content: content})
+	if err != nil {
+		b.logNotificationError(kind, user, channelID, err)
+	}
+}
+
+// channelInfo looks a channel up in the state cache before asking the API.
+func (b *Bot) channelInfo(channelID string) (*discordgo.Channel, error) {
+	if channel, err := b.Session.State.Channel(channelID); err == nil {
+		return channel, nil
+	}
+	return b.Discord.Channel(channelID)
+}
+
+// discordErrorCode returns the JSON error code of a Discord REST error, or 0 for any other error.
+func discordErrorCode(err error) int {
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Message != nil {
+		return restErr.Message.Code
+	}
+	return 0
+}
@@ -4,61 +4,203 @@ import (
 	"fmt"
 	"log"
 	"math"
+	"sort"
+	"strconv"
 	"strings"
-	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
 )
 
 const (
 	itemsPerPage = 5
 )
 
-// sendPaginatedList now edits the deferred interaction response instead of creating a new one.
-func (b *Bot) sendPaginatedList(s *discordgo.Session, i *discordgo.InteractionCreate, items []string, initialPage int) {
-	totalPages := int(math.Ceil(float64(len(items)) / float64(itemsPerPage)))
+// Paginated lists keep no state in memory: the list kind, guild, page and
+// filter are encoded in each component's custom ID and the page is re-queried
+// on every click, so buttons keep working across restarts.
+//
+//	page:<kind>:<guild>:<page>:<filter>:<button>  navigation buttons
+//	pagejump:<kind>:<guild>:<filter>              jump button and its modal
+//	pagefilter:<kind>:<guild>                     filter select menu
+const (
+	pagePrefix       = "page:"
+	pageJumpPrefix   = "pagejump:"
+	pageFilterPrefix = "pagefilter:"
+	pageJumpInput    = "page"
+)
 
-	// Ensure initialPage is valid
-	if totalPages == 0 {
-		totalPages = 1 // Prevent division by zero or invalid page numbers if items is empty
-	}
-	if initialPage < 1 {
-		initialPage = 1
+// Paginated list kinds.
+const (
+	listCreators = "creators"
+	listServers  = "servers"
+	listStatus   = "status"
+)
+
+type listFilter struct {
+	Value string
+	Label string
+}
+
+var listFilters = map[string][]listFilter{
+	listCreators: {
+		{Value: "all", Label: "All creators"},
+		{Value: "posts", Label: "Post notifications on"},
+		{Value: "live", Label: "Live notifications on"},
+		{Value: "muted", Label: "Muted"},
+		{Value: "streaming", Label: "Live right now"},
+	},
+	listServers: {
+		{Value: "all", Label: "All servers"},
+		{Value: "active", Label: "Monitoring creators"},
+		{Value: "idle", Label: "Not monitoring anyone"},
+	},
+	listStatus: {
+		{Value: "all", Label: "All creators"},
+		{Value: "problems", Label: "With problems"},
+		{Value: "healthy", Label: "Healthy"},
+	},
+}
+
+var listTitles = map[string]string{
+	listCreators: "Monitored Models",
+	listServers:  "Servers",
+	listStatus:   "Monitoring Status",
+}
+
+var listEmpty = map[string]string{
+	listCreators: "No models match this filter.",
+	listServers:  "No servers match this filter.",
+	listStatus:   "No models match this filter.",
+}
+
+// listAccess is what is needed to page through a list, matching the command that opened it.
+var listAccess = map[string]string{
+	listCreators: models.ScopeRead,
+	listServers:  accessOwner,
+	listStatus:   models.ScopeRead,
+}
+
+func validFilter(kind, filter string) bool {
+	for _, f := range listFilters[kind] {
+		if f.Value == filter {
+			return true
+		}
 	}
-	if initialPage > totalPages {
-		initialPage = totalPages
+	return false
+}
+
+// listItems re-queries the entries of a paginated list.
+func (b *Bot) listItems(kind, guildID, filter string) ([]string, error) {
+	switch kind {
+	case listCreators:
+		users, err := b.Repo.GetMonitoredUsersForGuild(guildID)
+		if err != nil {
+			return nil, err
+		}
+		now := b.Clock.Now()
+		var items []string
+		for _, user := range users {
+			switch filter {
+			case "posts":
+				if !user.PostsEnabled {
+					continue
+				}
+			case "live":
+				if !user.LiveEnabled {
+					continue
+				}
+			case "muted":
+				if !isMuted(user, now) {
+					continue
+				}
+			case "streaming":
+				if !user.IsLive {
+					continue
+				}
+			}
+			items = append(items, b.formatMonitoredUser(user))
+		}
+		return items, nil
+
+	case listStatus:
+		users, err := b.Repo.GetMonitoredUsersForGuild(guildID)
+		if err != nil {
+			return nil, err
+		}
+		var items []string
+		for _, user := range users {
+			health, checked := b.health.get(user.UserID)
+			healthy := healthIcon(user, health, checked) == "✅"
+			if (filter == "problems" && healthy) || (filter == "healthy" && !healthy) {
+				continue
+			}
+			items = append(items, b.formatCreatorStatus(user))
+		}
+		return items, nil
+
+	case listServers:
+		users, err := b.Repo.GetMonitoredUsers()
+		if err != nil {
+			return nil, err
+		}
+		counts := make(map[string]int)
+		for _, user := range users {
+			counts[user.GuildID]++
+		}
+
+		guilds := append([]*discordgo.Guild{}, b.Session.State.Guilds...)
+		sort.Slice(guilds, func(i, j int) bool {
+			return guilds[i].Name < guilds[j].Name
+		})
+
+		var items []string
+		for _, guild := range guilds {
+			count := counts[guild.ID]
+			if (filter == "active" && count == 0) || (filter == "idle" && count > 0) {
+				continue
+			}
+			items = append(items, fmt.Sprintf("**%s**\n  `ID:` %s\n  `Members:` %d\n  `Creators:` %d", guild.Name, guild.ID, guild.MemberCount, count))
+		}
+		return items, nil
 	}
 
-	// Create initial embed and components
-	embed := createPageEmbed(items, initialPage, totalPages)
-	components := createPaginationComponents(initialPage, totalPages)
+	return nil, fmt.Errorf("unknown list %q", kind)
+}
 
-	// Instead of s.InteractionRespond, we use s.InteractionResponseEdit
-	// to update the "Thinking..." message that was sent by the deferral.
-	// Note that the fields in WebhookEdit are pointers.
-	_, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
-		Embeds:     &[]*discordgo.MessageEmbed{embed},
-		Components: &components,
-	})
+// renderPage builds the embed and components for one page of a list. The
+// requested page is clamped to the pages that exist.
+func (b *Bot) renderPage(kind, guildID string, page int, filter string) (*discordgo.MessageEmbed, []discordgo.MessageComponent, error) {
+	items, err := b.listItems(kind, guildID, filter)
 	if err != nil {
-		log.Printf("Error editing interaction response for paginated list: %v", err)
-		return
+		return nil, nil, err
 	}
 
-	// Get the message object from the interaction we just edited.
-	// This is necessary to get the Message ID for the button collector.
-	msg, err := s.InteractionResponse(i.Interaction)
+	totalPages := max(1, int(math.Ceil(float64(len(items))/float64(itemsPerPage))))
+	page = min(max(page, 1), totalPages)
+
+	return createPageEmbed(kind, items, page, totalPages, filter), createPaginationComponents(kind, guildID, page, totalPages, filter), nil
+}
+
+// sendPaginatedList edits the deferred interaction response with the requested page.
+func (b *Bot) sendPaginatedList(s *discordgo.Session, i *discordgo.InteractionCreate, kind, guildID string, page int, filter string) {
+	embed, components, err := b.renderPage(kind, guildID, page, filter)
 	if err != nil {
-		log.Printf("Error getting interaction response message after edit: %v", err)
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching list: %v", err))
 		return
 	}
 
-	// Set up a collector for button interactions on the message we just sent.
-	b.setupPaginationCollector(s, i.Member.User.ID, msg.ID, i.ChannelID, items, totalPages)
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds:     &[]*discordgo.MessageEmbed{embed},
+		Components: &components,
+	})
+	if err != nil {
+		log.Printf("Error editing interaction response for paginated list: %v", err)
+	}
 }
 
 // createPageEmbed creates an embed for a specific page
-func createPageEmbed(items []string, page, totalPages int) *discordgo.MessageEmbed {
+func createPageEmbed(kind string, items []string, page, totalPages int, filter string) *discordgo.MessageEmbed {
 	startIdx := (page - 1) * itemsPerPage
 	endIdx := min(startIdx+itemsPerPage, len(items))
 
@@ -67,26 +209,58 @@ func createPageEmbed(items []string, page, totalPages int) *discordgo.MessageEmb
 		pageItems = items[startIdx:endIdx]
 	}
 
-	description := "No models are being monitored."
+	description := listEmpty[kind]
 	if len(pageItems) > 0 {
 		description = strings.Join(pageItems, "\n\n")
 	}
 
+	footer := fmt.Sprintf("Page %d of %d", page, totalPages)
+	if filter != "all" {
+		for _, f := range listFilters[kind] {
+			if f.Value == filter {
+				footer += " • " + f.Label
+			}
+		}
+	}
+
 	return &discordgo.MessageEmbed{
-		Title:       "Monitored Models",
+		Title:       listTitles[kind],
 		Description: description,
 		Footer: &discordgo.MessageEmbedFooter{
-			Text: fmt.Sprintf("Page %d of %d", page, totalPages),
+			Text: footer,
 		},
 		Color: 0x03b2f8, // A nice blue color
 	}
 }
 
-// createPaginationComponents creates the button components for pagination
-func createPaginationComponents(currentPage, totalPages int) []discordgo.MessageComponent {
-	// If there's only one page, no buttons are needed.
+func pageButtonID(kind, guildID string, page int, filter, button string) string {
+	return fmt.Sprintf("%s%s:%s:%d:%s:%s", pagePrefix, kind, guildID, page, filter, button)
+}
+
+// createPaginationComponents creates the navigation buttons and filter menu.
+func createPaginationComponents(kind, guildID string, currentPage, totalPages int, filter string) []discordgo.MessageComponent {
+	var options []discordgo.SelectMenuOption
+	for _, f := range listFilters[kind] {
+		options = append(options, discordgo.SelectMenuOption{
+			Label:   f.Label,
+			Value:   f.Value,
+			Default: f.Value == filter,
+		})
+	}
+
+	filterRow := discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.SelectMenu{
+				CustomID:    fmt.Sprintf("%s%s:%s", pageFilterPrefix, kind, guildID),
+				Placeholder: "Filter",
+				Options:     options,
+			},
+		},
+	}
+
+	// A single page needs no navigation, but the filter stays available.
 	if totalPages <= 1 {
-		return nil
+		return []discordgo.MessageComponent{filterRow}
 	}
 
 	return []discordgo.MessageComponent{
@@ -95,127 +269,167 @@ func createPaginationComponents(currentPage, totalPages int) []discordgo.Message
 				discordgo.Button{
 					Label:    "First",
 					Style:    discordgo.SecondaryButton,
-					CustomID: "first_page",
+					CustomID: pageButtonID(kind, guildID, 1, filter, "first"),
 					Emoji:    &discordgo.ComponentEmoji{Name: "⏮️"},
 					Disabled: currentPage == 1,
 				},
 				discordgo.Button{
 					Label:    "Previous",
 					Style:    discordgo.PrimaryButton,
-					CustomID: "prev_page",
+					CustomID: pageButtonID(kind, guildID, currentPage-1, filter, "prev"),
 					Emoji:    &discordgo.ComponentEmoji{Name: "⬅️"},
 					Disabled: currentPage == 1,
 				},
+				discordgo.Button{
+					Label:    fmt.Sprintf("%d/%d", currentPage, totalPages),
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("%s%s:%s:%s", pageJumpPrefix, kind, guildID, filter),
+				},
 				discordgo.Button{
 					Label:    "Next",
 					Style:    discordgo.PrimaryButton,
-					CustomID: "next_page",
+					CustomID: pageButtonID(kind, guildID, currentPage+1, filter, "next"),
 					Emoji:    &discordgo.ComponentEmoji{Name: "➡️"},
 					Disabled: currentPage == totalPages,
 				},
 				discordgo.Button{
 					Label:    "Last",
 					Style:    discordgo.SecondaryButton,
-					CustomID: "last_page",
+					CustomID: pageButtonID(kind, guildID, totalPages, filter, "last"),
 					Emoji:    &discordgo.ComponentEmoji{Name: "⏭️"},
 					Disabled: currentPage == totalPages,
 				},
 			},
 		},
+		filterRow,
 	}
 }
 
-// setupPaginationCollector sets up a collector for pagination button interactions
-func (b *Bot) setupPaginationCollector(s *discordgo.Session, userID, messageID, channelID string, items []string, totalPages int) {
-	// Create a handler for button interactions
-	handlerFunc := s.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
-		if i.Type != discordgo.InteractionMessageComponent || i.Message.ID != messageID {
-			return
-		}
+// authorizeList checks that the clicking member may see the list and that the
+// list belongs to the guild the message is in.
+func (b *Bot) authorizeList(s *discordgo.Session, i *discordgo.InteractionCreate, kind, guildID string) bool {
+	required, ok := listAccess[kind]
+	if !ok || (kind != listServers && guildID != i.GuildID) || !b.authorize(s, i, required) {
+		b.respondToInteraction(s, i, "You do not have permission to use these controls.", true)
+		return false
+	}
+	return true
+}
 
-		// Only allow the original command user to use the buttons
-		if i.Member.User.ID != userID {
-			s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-				Type: discordgo.InteractionResponseChannelMessageWithSource,
-				Data: &discordgo.InteractionResponseData{
-					Content: "Only the user who ran the command can use these buttons.",
-					Flags:   discordgo.MessageFlagsEphemeral,
-				},
-			})
-			return
-		}
+// updatePage replaces the list message with the given page.
+func (b *Bot) updatePage(s *discordgo.Session, i *discordgo.InteractionCreate, kind, guildID string, page int, filter string) {
+	embed, components, err := b.renderPage(kind, guildID, page, filter)
+	if err != nil {
+		log.Printf("Error rendering %s page: %v", kind, err)
+		b.respondToInteraction(s, i, "An error occurred while loading this page.", true)
+		return
+	}
 
-		// Get the current page from the footer
-		var currentPage int
-		if len(i.Message.Embeds) > 0 && i.Message.Embeds[0].Footer != nil {
-			fmt.Sscanf(i.Message.Embeds[0].Footer.Text, "Page %d of %d", &currentPage, &totalPages)
-		}
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: components,
+		},
+	})
+	if err != nil {
+		log.Printf("Error updating paginated message: %v", err)
+	}
+}
 
-		// Determine the new page based on the button pressed
-		newPage := currentPage
-		switch i.MessageComponentData().CustomID {
-		case "prev_page":
-			if currentPage > 1 {
-				newPage = currentPage - 1
-			}
-		case "next_page":
-			if currentPage < totalPages {
-				newPage = currentPage + 1
-			}
-		case "first_page":
-			newPage = 1
-		case "last_page":
-			newPage = totalPages
-		default:
-			// Acknowledge the interaction to prevent it from failing, but do nothing.
-			s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
-			return
-		}
+func (b *Bot) handlePageButton(s *discordgo.Session, i *discordgo.InteractionCreate, data string) {
+	parts := strings.Split(data, ":")
+	if len(parts) != 5 {
+		return
+	}
+	kind, guildID, filter := parts[0], parts[1], parts[3]
+	page, err := strconv.Atoi(parts[2])
+	if err != nil || !validFilter(kind, filter) || !b.authorizeList(s, i, kind, guildID) {
+		return
+	}
 
-		// If the page hasn't changed, do nothing.
-		if newPage == currentPage {
-			s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredMessageUpdate})
-			return
-		}
+	b.updatePage(s, i, kind, guildID, page, filter)
+}
+
+func (b *Bot) handlePageFilter(s *discordgo.Session, i *discordgo.InteractionCreate, data string) {
+	kind, guildID, ok := strings.Cut(data, ":")
+	values := i.MessageComponentData().Values
+	if !ok || len(values) == 0 || !validFilter(kind, values[0]) || !b.authorizeList(s, i, kind, guildID) {
+		return
+	}
+
+	b.updatePage(s, i, kind, guildID, 1, values[0])
+}
 
-		// Create the new embed and components
-		embed := createPageEmbed(items, newPage, totalPages)
-		components := createPaginationComponents(newPage, totalPages)
+// handlePageJumpButton opens the "Jump to page" modal.
+func (b *Bot) handlePageJumpButton(s *discordgo.Session, i *discordgo.InteractionCreate, data string) {
+	parts := strings.Split(data, ":")
+	if len(parts) != 3 || !b.authorizeList(s, i, parts[0], parts[1]) {
+		return
+	}
 
-		// Update the message by responding to the button interaction
-		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseUpdateMessage,
-			Data: &discordgo.InteractionResponseData{
-				Embeds:     []*discordgo.MessageEmbed{embed},
-				Components: components,
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: pageJumpPrefix + data,
+			Title:    "Jump to page",
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    pageJumpInput,
+							Label:       "Page number",
+							Style:       discordgo.TextInputShort,
+							Placeholder: "1",
+							Required:    true,
+							MaxLength:   5,
+						},
+					},
+				},
 			},
-		})
-		if err != nil {
-			log.Printf("Error updating paginated message: %v", err)
-		}
+		},
 	})
+	if err != nil {
+		log.Printf("Error opening jump to page modal: %v", err)
+	}
+}
 
-	// Remove the handler and buttons after 5 minutes of inactivity
-	time.AfterFunc(5*time.Minute, func() {
-		handlerFunc() // This invokes the returned function from AddHandler, which removes it.
+// handlePageJumpModal shows the page entered in the "Jump to page" modal.
+func (b *Bot) handlePageJumpModal(s *discordgo.Session, i *discordgo.InteractionCreate, data string) {
+	parts := strings.Split(data, ":")
+	if len(parts) != 3 {
+		return
+	}
+	kind, guildID, filter := parts[0], parts[1], parts[2]
+	if !validFilter(kind, filter) || !b.authorizeList(s, i, kind, guildID) {
+		return
+	}
 
-		// Get the final state of the embed
-		msg, err := s.ChannelMessage(channelID, messageID)
-		if err != nil {
-			// Message might have been deleted, which is fine.
-			return
+	var input string
+	for _, row := range i.ModalSubmitData().Components {
+		if actions, ok := row.(*discordgo.ActionsRow); ok {
+			for _, component := range actions.Components {
+				if text, ok := component.(*discordgo.TextInput); ok && text.CustomID == pageJumpInput {
+					input = text.Value
+				}
+			}
 		}
+	}
 
-		// Edit the message to remove the buttons.
-		_, err = s.ChannelMessageEditComplex(&discordgo.MessageEdit{
-			Channel:    channelID,
-			ID:         messageID,
-			Embeds:     &msg.Embeds,                     // Keep the existing embeds
-			Components: &[]discordgo.MessageComponent{}, // Set components to an empty slice
-		})
+	page, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil {
+		b.respondToInteraction(s, i, "Please enter a page number.", true)
+		return
+	}
 
-		if err != nil {
-			log.Printf("Error removing pagination buttons after timeout: %v", err)
-		}
-	})
+	b.updatePage(s, i, kind, guildID, page, filter)
+}
+
+// filterChoices offers a list's filters as slash command choices.
+func filterChoices(kind string) []*discordgo.ApplicationCommandOptionChoice {
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for _, f := range listFilters[kind] {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: f.Label, Value: f.Value})
+	}
+	return choices
 }
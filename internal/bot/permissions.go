@@ -0,0 +1,185 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+// channelPermission is a permission the bot needs in a notification channel.
+// Critical permissions stop a notification from being delivered at all.
+type channelPermission struct {
+	Flag     int64
+	Name     string
+	Critical bool
+}
+
+// requiredChannelPermissions lists what the bot needs to deliver the
+// notifications of a subscription in the channel.
+func requiredChannelPermissions(s *discordgo.Session, user models.MonitoredUser, channel *discordgo.Channel, mentionRole string) []channelPermission {
+	send := channelPermission{discordgo.PermissionSendMessages, "Send Messages", true}
+	if channel.IsThread() {
+		send = channelPermission{discordgo.PermissionSendMessagesInThreads, "Send Messages in Threads", true}
+	}
+
+	required := []channelPermission{
+		{discordgo.PermissionViewChannel, "View Channel", true},
+		send,
+		{discordgo.PermissionEmbedLinks, "Embed Links", true},
+	}
+
+	// Roles that aren't mentionable only ping with Mention Everyone.
+	if mentionRole != "" {
+		if role, err := s.State.Role(user.GuildID, mentionRole); err != nil || !role.Mentionable {
+			required = append(required, channelPermission{discordgo.PermissionMentionEveryone, "Mention Everyone", false})
+		}
+	}
+	if user.WebhookDelivery {
+		required = append(required, channelPermission{discordgo.PermissionManageWebhooks, "Manage Webhooks", false})
+	}
+	if (user.ThreadPosts || user.ThreadLive) && !channel.IsThread() && channel.Type != discordgo.ChannelTypeGuildForum {
+		required = append(required, channelPermission{discordgo.PermissionCreatePublicThreads, "Create Public Threads", false})
+	}
+	return required
+}
+
+// missingPermissions returns the required permissions not included in granted.
+func missingPermissions(granted int64, required []channelPermission) []channelPermission {
+	if granted&discordgo.PermissionAdministrator != 0 {
+		return nil
+	}
+
+	var missing []channelPermission
+	for _, permission := range required {
+		if granted&permission.Flag == 0 {
+			missing = append(missing, permission)
+		}
+	}
+	return missing
+}
+
+// hasCriticalPermission reports whether any of the permissions is critical.
+func hasCriticalPermission(permissions []channelPermission) bool {
+	for _, permission := range permissions {
+		if permission.Critical {
+			return true
+		}
+	}
+	return false
+}
+
+func permissionNames(permissions []channelPermission) string {
+	names := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		names = append(names, "**"+permission.Name+"**")
+	}
+	return strings.Join(names, ", ")
+}
+
+// botChannelPermissions computes the bot's effective permissions in a channel.
+// Threads use the permission overwrites of their parent channel.
+func (b *Bot) botChannelPermissions(guildID string, channel *discordgo.Channel) (int64, error) {
+	guild, err := b.Session.State.Guild(guildID)
+	if err != nil {
+		if guild, err = b.Discord.Guild(guildID); err != nil {
+			return 0, err
+		}
+	}
+
+	botID := b.Session.State.User.ID
+	member, err := b.Session.State.Member(guildID, botID)
+	if err != nil {
+		if member, err = b.Discord.GuildMember(guildID, botID); err != nil {
+			return 0, err
+		}
+	}
+
+	if channel.IsThread() {
+		if channel, err = b.channelInfo(channel.ParentID); err != nil {
+			return 0, err
+		}
+	}
+
+	return effectivePermissions(guild, member, botID, channel.PermissionOverwrites), nil
+}
+
+// effectivePermissions applies Discord's permission hierarchy: the guild
+// owner and administrators get everything, otherwise the @everyone and member
+// role permissions are combined and then adjusted by the channel's @everyone,
+// role and member overwrites, in that order.
+func effectivePermissions(guild *discordgo.Guild, member *discordgo.Member, userID string, overwrites []*discordgo.PermissionOverwrite) int64 {
+	if guild.OwnerID == userID {
+		return discordgo.PermissionAll
+	}
+
+	memberRoles := make(map[string]bool, len(member.Roles))
+	for _, roleID := range member.Roles {
+		memberRoles[roleID] = true
+	}
+
+	var permissions int64
+	for _, role := range guild.Roles {
+		// The @everyone role shares the guild's ID.
+		if role.ID == guild.ID || memberRoles[role.ID] {
+			permissions |= role.Permissions
+		}
+	}
+	if permissions&discordgo.PermissionAdministrator != 0 {
+		return discordgo.PermissionAll
+	}
+
+	for _, overwrite := range overwrites {
+		if overwrite.Type == discordgo.PermissionOverwriteTypeRole && overwrite.ID == guild.ID {
+			permissions = permissions&^overwrite.Deny | overwrite.Allow
+		}
+	}
+
+	var allow, deny int64
+	for _, overwrite := range overwrites {
+		if overwrite.Type == discordgo.PermissionOverwriteTypeRole && memberRoles[overwrite.ID] {
+			allow |= overwrite.Allow
+			deny |= overwrite.Deny
+		}
+	}
+	permissions = permissions&^deny | allow
+
+	for _, overwrite := range overwrites {
+		if overwrite.Type == discordgo.PermissionOverwriteTypeMember && overwrite.ID == userID {
+			permissions = permissions&^overwrite.Deny | overwrite.Allow
+		}
+	}
+
+	return permissions
+}
+
+// validateNotificationChannel checks that the subscription's notifications can
+// be delivered to the channel. It returns a message for the member describing
+// any problem and whether delivery can work at all; missing optional
+// permissions only degrade notifications.
+func (b *Bot) validateNotificationChannel(channel *discordgo.Channel, user models.MonitoredUser, mentionRole string) (string, bool) {
+	if channel == nil || !isSupportedNotificationChannel(channel) {
+		return "notifications can only be sent to text, announcement, thread or forum channels.", false
+	}
+
+	// Resolved command options carry a partial channel without overwrites.
+	if full, err := b.channelInfo(channel.ID); err == nil {
+		channel = full
+	}
+
+	permissions, err := b.botChannelPermissions(user.GuildID, channel)
+	if err != nil {
+		log.Printf("Error computing permissions in channel %s: %v", channel.ID, err)
+		return fmt.Sprintf("My permissions in %s could not be verified, so notifications may not be delivered there.", channel.Mention()), true
+	}
+
+	missing := missingPermissions(permissions, requiredChannelPermissions(b.Session, user, channel, mentionRole))
+	if len(missing) == 0 {
+		return "", true
+	}
+
+	return fmt.Sprintf("I'm missing %s in %s. Grant them to my role or in the channel's permission settings.",
+		permissionNames(missing), channel.Mention()), !hasCriticalPermission(missing)
+}
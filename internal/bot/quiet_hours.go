@@ -0,0 +1,158 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+const maxMuteDuration = 30 * 24 * time.Hour
+
+// inQuietHours reports whether now falls inside the guild's quiet window.
+// Windows may wrap past midnight, e.g. 22:00–06:00.
+func inQuietHours(settings models.GuildSettings, now time.Time) bool {
+	if !settings.QuietHoursEnabled || settings.QuietStart == settings.QuietEnd {
+		return false
+	}
+
+	local := now.In(settings.Location())
+	minute := local.Hour()*60 + local.Minute()
+
+	if settings.QuietStart < settings.QuietEnd {
+		return minute >= settings.QuietStart && minute < settings.QuietEnd
+	}
+	return minute >= settings.QuietStart || minute < settings.QuietEnd
+}
+
+// quietHoursEnd returns the next time the quiet window closes after now.
+func quietHoursEnd(settings models.GuildSettings, now time.Time) time.Time {
+	loc := settings.Location()
+	local := now.In(loc)
+
+	end := time.Date(local.Year(), local.Month(), local.Day(), settings.QuietEnd/60, settings.QuietEnd%60, 0, 0, loc)
+	if !end.After(local) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
+}
+
+func isMuted(user models.MonitoredUser, now time.Time) bool {
+	return user.MutedUntil > now.Unix()
+}
+
+// parseClock parses an "HH:MM" time of day into minutes after midnight.
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// parseMuteDuration accepts Go durations such as "90m" or "12h" plus day and week suffixes like "2d" and "1w".
+func parseMuteDuration(value string) (time.Duration, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	var d time.Duration
+	var err error
+	switch {
+	case strings.HasSuffix(value, "d"), strings.HasSuffix(value, "w"):
+		unit := 24 * time.Hour
+		if strings.HasSuffix(value, "w") {
+			unit = 7 * 24 * time.Hour
+		}
+		var n int
+		n, err = strconv.Atoi(value[:len(value)-1])
+		d = time.Duration(n) * unit
+	default:
+		d, err = time.ParseDuration(value)
+	}
+
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q, use values like 30m, 8h or 2d", value)
+	}
+	if d > maxMuteDuration {
+		return 0, fmt.Errorf("duration %q is longer than the 30 day maximum", value)
+	}
+	return d, nil
+}
+
+// queueNotification stores a notification until the guild's quiet hours end.
+func (b *Bot) queueNotification(n notification, releaseAt time.Time) error {
+	embedJSON, err := json.Marshal(n.Embed)
+	if err != nil {
+		return err
+	}
+
+	return b.Repo.AddQueuedNotification(&models.QueuedNotification{
+		GuildID:   n.User.GuildID,
+		UserID:    n.User.UserID,
+		Kind:      n.Kind,
+		ChannelID: n.Channel,
+		Content:   n.Mention,
+		Embed:     string(embedJSON),
+		ReleaseAt: releaseAt.Unix(),
+		CreatedAt: b.Clock.Now().Unix(),
+	})
+}
+
+// releaseQueuedNotifications sends notifications whose quiet window has ended.
+// Those of guilds the bot has left wait until the guild is restored or purged.
+func (b *Bot) releaseQueuedNotifications(now time.Time) {
+	queued, err := b.Repo.GetDueQueuedNotifications(now.Unix())
+	if err != nil {
+		log.Printf("Error fetching queued notifications: %v", err)
+		return
+	}
+
+	for _, q := range queued {
+		if err := b.Repo.DeleteQueuedNotification(q.ID); err != nil {
+			log.Printf("Error removing queued notification %d: %v", q.ID, err)
+			continue
+		}
+
+		user, err := b.Repo.GetMonitoredUser(q.GuildID, q.UserID)
+		if err != nil || user == nil {
+			// Removed from the guild while the notification was queued.
+			continue
+		}
+		if isMuted(*user, now) {
+			continue
+		}
+
+		var embedMsg discordgo.MessageEmbed
+		if err := json.Unmarshal([]byte(q.Embed), &embedMsg); err != nil {
+			log.Printf("Error decoding queued notification %d: %v", q.ID, err)
+			continue
+		}
+
+		b.sendNotification(notification{
+			Kind:    q.Kind,
+			User:    *user,
+			Channel: q.ChannelID,
+			Mention: q.Content,
+			Embed:   &embedMsg,
+		})
+	}
+}
+
+func (b *Bot) clearExpiredMutes(now time.Time) {
+	cleared, err := b.Repo.ClearExpiredMutes(now.Unix())
+	if err != nil {
+		log.Printf("Error clearing expired mutes: %v", err)
+		return
+	}
+	if cleared > 0 {
+		log.Printf("Cleared %d expired mutes", cleared)
+	}
+}
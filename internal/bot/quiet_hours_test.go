@@ -0,0 +1,150 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+// fixedClock always returns the same instant, so its timers never fire.
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+func (fixedClock) AfterFunc(time.Duration, func()) {}
+
+func clockAt(t *testing.T, value string) Clock {
+	t.Helper()
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatalf("parsing %q: %v", value, err)
+	}
+	return fixedClock(at)
+}
+
+func quietSettings(timezone, start, end string) models.GuildSettings {
+	startMinute, _ := parseClock(start)
+	endMinute, _ := parseClock(end)
+	return models.GuildSettings{
+		Timezone:          timezone,
+		QuietHoursEnabled: true,
+		QuietStart:        startMinute,
+		QuietEnd:          endMinute,
+	}
+}
+
+func TestInQuietHours(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings models.GuildSettings
+		now      string
+		want     bool
+	}{
+		{"same day inside", quietSettings("UTC", "09:00", "17:00"), "2024-05-01T12:00:00Z", true},
+		{"same day at end", quietSettings("UTC", "09:00", "17:00"), "2024-05-01T17:00:00Z", false},
+		{"same day before start", quietSettings("UTC", "09:00", "17:00"), "2024-05-01T08:59:00Z", false},
+		{"across midnight before midnight", quietSettings("UTC", "22:00", "06:00"), "2024-05-01T23:30:00Z", true},
+		{"across midnight after midnight", quietSettings("UTC", "22:00", "06:00"), "2024-05-02T05:59:00Z", true},
+		{"across midnight daytime", quietSettings("UTC", "22:00", "06:00"), "2024-05-02T12:00:00Z", false},
+		{"start equals end", quietSettings("UTC", "22:00", "22:00"), "2024-05-01T22:00:00Z", false},
+		{"disabled", models.GuildSettings{QuietStart: 0, QuietEnd: 1439}, "2024-05-01T12:00:00Z", false},
+		{"guild timezone", quietSettings("Asia/Tokyo", "22:00", "06:00"), "2024-05-01T14:00:00Z", true},
+		{"guild timezone daytime", quietSettings("Asia/Tokyo", "22:00", "06:00"), "2024-05-01T22:00:00Z", false},
+		// 02:30 UTC is 22:30 in New York in summer but 21:30 in winter.
+		{"daylight saving time", quietSettings("America/New_York", "22:00", "06:00"), "2024-07-01T02:30:00Z", true},
+		{"standard time", quietSettings("America/New_York", "22:00", "06:00"), "2024-01-01T02:30:00Z", false},
+		{"standard time after end", quietSettings("America/New_York", "22:00", "06:00"), "2024-01-01T11:30:00Z", false},
+		{"unknown timezone falls back to UTC", quietSettings("Nowhere/Else", "22:00", "06:00"), "2024-05-01T23:00:00Z", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := clockAt(t, tt.now)
+			if got := inQuietHours(tt.settings, clock.Now()); got != tt.want {
+				t.Errorf("inQuietHours at %s = %t, want %t", tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuietHoursEnd(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings models.GuildSettings
+		now      string
+		want     string
+	}{
+		{"later today", quietSettings("UTC", "09:00", "17:00"), "2024-05-01T12:00:00Z", "2024-05-01T17:00:00Z"},
+		{"after midnight", quietSettings("UTC", "22:00", "06:00"), "2024-05-01T23:00:00Z", "2024-05-02T06:00:00Z"},
+		{"before midnight end", quietSettings("UTC", "22:00", "06:00"), "2024-05-02T01:00:00Z", "2024-05-02T06:00:00Z"},
+		{"exactly at end", quietSettings("UTC", "22:00", "06:00"), "2024-05-02T06:00:00Z", "2024-05-03T06:00:00Z"},
+		{"guild timezone", quietSettings("Asia/Tokyo", "22:00", "06:00"), "2024-05-01T14:00:00Z", "2024-05-01T21:00:00Z"},
+		// Clocks spring forward at 02:00 on 2024-03-10, so the night is an hour shorter.
+		{"spring forward", quietSettings("America/New_York", "22:00", "06:00"), "2024-03-10T04:00:00Z", "2024-03-10T10:00:00Z"},
+		// Clocks fall back at 02:00 on 2024-11-03, so the night is an hour longer.
+		{"fall back", quietSettings("America/New_York", "22:00", "06:00"), "2024-11-03T03:00:00Z", "2024-11-03T11:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := clockAt(t, tt.now)
+			want, _ := time.Parse(time.RFC3339, tt.want)
+			if got := quietHoursEnd(tt.settings, clock.Now()); !got.Equal(want) {
+				t.Errorf("quietHoursEnd at %s = %s, want %s", tt.now, got.UTC().Format(time.RFC3339), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMuteDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "30m", want: 30 * time.Minute},
+		{value: "8h", want: 8 * time.Hour},
+		{value: " 2D ", want: 48 * time.Hour},
+		{value: "1w", want: 7 * 24 * time.Hour},
+		{value: "30d", want: 30 * 24 * time.Hour},
+		{value: "31d", wantErr: true},
+		{value: "5w", wantErr: true},
+		{value: "0m", wantErr: true},
+		{value: "-1h", wantErr: true},
+		{value: "-2d", wantErr: true},
+		{value: "d", wantErr: true},
+		{value: "soon", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseMuteDuration(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseMuteDuration(%q) = %s, want an error", tt.value, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseMuteDuration(%q) = %s, %v; want %s", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestIsMuted(t *testing.T) {
+	clock := clockAt(t, "2024-05-01T12:00:00Z")
+	now := clock.Now()
+
+	if !isMuted(models.MonitoredUser{MutedUntil: now.Add(time.Minute).Unix()}, now) {
+		t.Errorf("subscription muted until a minute from now isn't muted")
+	}
+	if isMuted(models.MonitoredUser{MutedUntil: now.Unix()}, now) {
+		t.Errorf("subscription whose mute ends now is still muted")
+	}
+	if isMuted(models.MonitoredUser{}, now) {
+		t.Errorf("subscription that was never muted is muted")
+	}
+}
@@ -0,0 +1,274 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// deferMode is how the registry acknowledges a command before its handler runs.
+type deferMode int
+
+const (
+	deferNone      deferMode = iota // the handler responds itself
+	deferPublic                     // "thinking…" visible to everyone
+	deferEphemeral                  // "thinking…" visible only to the invoking member
+)
+
+// commandOptions are a command's options keyed by name.
+type commandOptions map[string]*discordgo.ApplicationCommandInteractionDataOption
+
+func (o commandOptions) Has(name string) bool {
+	_, ok := o[name]
+	return ok
+}
+
+func (o commandOptions) String(name string) string {
+	if option, ok := o[name]; ok {
+		return option.StringValue()
+	}
+	return ""
+}
+
+func (o commandOptions) Bool(name string) bool {
+	if option, ok := o[name]; ok {
+		return option.BoolValue()
+	}
+	return false
+}
+
+func (o commandOptions) Int(name string) int64 {
+	if option, ok := o[name]; ok {
+		return option.IntValue()
+	}
+	return 0
+}
+
+func (o commandOptions) Channel(s *discordgo.Session, name string) *discordgo.Channel {
+	if option, ok := o[name]; ok {
+		return option.ChannelValue(s)
+	}
+	return nil
+}
+
+func (o commandOptions) Role(s *discordgo.Session, guildID, name string) *discordgo.Role {
+	if option, ok := o[name]; ok {
+		return option.RoleValue(s, guildID)
+	}
+	return nil
+}
+
+type commandHandler func(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions)
+
+// command declares a slash command. Path is either a top-level name such as
+// "subscribe" or a group and subcommand such as "creator add". Aliases are
+// extra top-level names registered with the same options, kept so existing
+// muscle memory keeps working while commands move into groups. NewCreator
+// lets the "username" option name a creator the guild doesn't monitor yet;
+// otherwise it must match a monitored creator before the handler runs.
+// Commands only work inside a server unless AllowDM is set; a group is
+// offered in DMs only when all of its subcommands are.
+type command struct {
+	Path        string
+	Description string
+	Options     []*discordgo.ApplicationCommandOption
+	Access      string
+	Defer       deferMode
+	Handler     commandHandler
+	Aliases     []string
+	NewCreator  bool
+	AllowDM     bool
+}
+
+// middleware wraps a command's handler. It receives the command so it can
+// read its declaration.
+type middleware func(cmd *command, next commandHandler) commandHandler
+
+type commandRegistry struct {
+	commands   []*command
+	byName     map[string]*command
+	groups     map[string]string
+	middleware []middleware
+}
+
+func newCommandRegistry() *commandRegistry {
+	return &commandRegistry{
+		byName: make(map[string]*command),
+		groups: make(map[string]string),
+	}
+}
+
+// group sets the description of a command group such as "creator".
+func (r *commandRegistry) group(name, description string) {
+	r.groups[name] = description
+}
+
+func (r *commandRegistry) add(cmd *command) {
+	if _, exists := r.byName[cmd.Path]; exists {
+		panic(fmt.Sprintf("command %q registered twice", cmd.Path))
+	}
+	r.commands = append(r.commands, cmd)
+	r.byName[cmd.Path] = cmd
+	for _, alias := range cmd.Aliases {
+		if _, exists := r.byName[alias]; exists {
+			panic(fmt.Sprintf("command alias %q registered twice", alias))
+		}
+		r.byName[alias] = cmd
+	}
+}
+
+// use appends middleware. The first middleware added runs outermost.
+func (r *commandRegistry) use(mw ...middleware) {
+	r.middleware = append(r.middleware, mw...)
+}
+
+// definitions builds the application commands to register with Discord, in
+// registration order, with subcommands folded into their groups.
+func (r *commandRegistry) definitions() []*discordgo.ApplicationCommand {
+	var definitions []*discordgo.ApplicationCommand
+	groups := make(map[string]*discordgo.ApplicationCommand)
+
+	for _, cmd := range r.commands {
+		groupName, subName, isSub := strings.Cut(cmd.Path, " ")
+		if !isSub {
+			definitions = append(definitions, &discordgo.ApplicationCommand{
+				Name:         cmd.Path,
+				Description:  cmd.Description,
+				Options:      cmd.Options,
+				DMPermission: &cmd.AllowDM,
+			})
+		} else {
+			group, ok := groups[groupName]
+			if !ok {
+				allowDM := true
+				group = &discordgo.ApplicationCommand{
+					Name:         groupName,
+					Description:  r.groups[groupName],
+					DMPermission: &allowDM,
+				}
+				groups[groupName] = group
+				definitions = append(definitions, group)
+			}
+			if !cmd.AllowDM {
+				*group.DMPermission = false
+			}
+			group.Options = append(group.Options, &discordgo.ApplicationCommandOption{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        subName,
+				Description: cmd.Description,
+				Options:     cmd.Options,
+			})
+		}
+
+		for _, alias := range cmd.Aliases {
+			definitions = append(definitions, &discordgo.ApplicationCommand{
+				Name:         alias,
+				Description:  cmd.Description,
+				Options:      cmd.Options,
+				DMPermission: &cmd.AllowDM,
+			})
+		}
+	}
+
+	return definitions
+}
+
+// resolve finds the command an interaction invokes and collects the options
+// of the innermost subcommand.
+func (r *commandRegistry) resolve(data discordgo.ApplicationCommandInteractionData) (*command, commandOptions) {
+	path := data.Name
+	options := data.Options
+	if len(options) == 1 && options[0].Type == discordgo.ApplicationCommandOptionSubCommand {
+		path += " " + options[0].Name
+		options = options[0].Options
+	}
+
+	opts := make(commandOptions, len(options))
+	for _, option := range options {
+		opts[option.Name] = option
+	}
+	return r.byName[path], opts
+}
+
+func (r *commandRegistry) dispatch(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	cmd, opts := r.resolve(i.ApplicationCommandData())
+	if cmd == nil {
+		log.Printf("Received unknown command /%s", i.ApplicationCommandData().Name)
+		return
+	}
+
+	handler := cmd.Handler
+	for idx := len(r.middleware) - 1; idx >= 0; idx-- {
+		handler = r.middleware[idx](cmd, handler)
+	}
+	handler(s, i, opts)
+}
+
+// recoverMiddleware keeps a panicking handler from taking the bot down and
+// tells the member something went wrong.
+func (b *Bot) recoverMiddleware(cmd *command, next commandHandler) commandHandler {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				log.Printf("Panic in /%s: %v\n%s", cmd.Path, rec, debug.Stack())
+				// Deferred commands have already been acknowledged; the others
+				// either haven't been or respond themselves.
+				const message = "An internal error occurred while running this command."
+				if cmd.Defer == deferNone {
+					b.respondToInteraction(s, i, message, true)
+				} else {
+					b.editInteractionResponse(s, i, message)
+				}
+			}
+		}()
+		next(s, i, opts)
+	}
+}
+
+func (b *Bot) logMiddleware(cmd *command, next commandHandler) commandHandler {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+		start := time.Now()
+		next(s, i, opts)
+		if duration := time.Since(start); duration > time.Second {
+			log.Printf("/%s by %s in guild %s took %s", cmd.Path, interactionUserID(i), i.GuildID, duration.Round(time.Millisecond))
+		}
+	}
+}
+
+func (b *Bot) authorizeMiddleware(cmd *command, next commandHandler) commandHandler {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+		if i.GuildID == "" && !cmd.AllowDM {
+			// Discord hides these in DMs; this catches stale registrations.
+			b.respondToInteraction(s, i, "This command only works inside a server.", true)
+			return
+		}
+		if !b.authorize(s, i, cmd.Access) {
+			b.respondToInteraction(s, i, "You do not have permission to use this command.", true)
+			log.Printf("Permission denied for user %s on /%s", interactionUserID(i), cmd.Path)
+			return
+		}
+		next(s, i, opts)
+	}
+}
+
+func (b *Bot) deferMiddleware(cmd *command, next commandHandler) commandHandler {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+		if cmd.Defer != deferNone {
+			response := &discordgo.InteractionResponse{
+				Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+			}
+			if cmd.Defer == deferEphemeral {
+				response.Data = &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral}
+			}
+			if err := s.InteractionRespond(i.Interaction, response); err != nil {
+				log.Printf("Error deferring /%s: %v", cmd.Path, err)
+				return
+			}
+		}
+		next(s, i, opts)
+	}
+}
@@ -0,0 +1,67 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/events"
+)
+
+// announceRename tells guilds that opted in that a creator changed their
+// username, in the channel their post notifications go to.
+func (b *Bot) announceRename(e events.UsernameChanged) {
+	for _, user := range e.Subscriptions {
+		settings, err := b.Repo.GetGuildSettings(user.GuildID)
+		if err != nil {
+			log.Printf("Error fetching settings for guild %s, not announcing rename of %s: %v", user.GuildID, e.NewUsername, err)
+			continue
+		}
+		if !settings.AnnounceRenames {
+			continue
+		}
+
+		b.sendNotice(user, "rename", fmt.Sprintf("✏️ **%s** is now **%s** on Fansly: <https://fansly.com/%s>", e.OldUsername, e.NewUsername, e.NewUsername))
+	}
+}
+
+// formatAliases lists the names a creator used before, or "" if none.
+func (b *Bot) formatAliases(userID string) string {
+	aliases, err := b.Repo.GetCreatorAliases(userID)
+	if err != nil {
+		log.Printf("Error fetching old usernames of creator %s: %v", userID, err)
+		return ""
+	}
+	if len(aliases) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		names = append(names, alias.Username)
+	}
+	return strings.Join(names, ", ")
+}
+
+func (b *Bot) handleAnnounceRenamesCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	enabled := opts.Bool("enabled")
+
+	settings, err := b.Repo.GetGuildSettings(i.GuildID)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching server settings: %v", err))
+		return
+	}
+
+	settings.AnnounceRenames = enabled
+	if err := b.Repo.SaveGuildSettings(settings); err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error saving server settings: %v", err))
+		return
+	}
+
+	if !enabled {
+		b.editInteractionResponse(s, i, "Username changes will no longer be announced.")
+		return
+	}
+	b.editInteractionResponse(s, i, "When a monitored creator changes their username, it will be announced in their post notification channel.")
+}
@@ -0,0 +1,142 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+var fanslyURLRegex = regexp.MustCompile(`(?i)(?:https?://)?(?:www\.)?(?:fans\.ly|fansly\.com)/([^/\s?#]+)`)
+
+// normalizeUsername turns what a member typed into a bare username: it trims
+// spaces, takes the username from a pasted Fansly link and drops a leading @.
+func normalizeUsername(input string) string {
+	input = strings.TrimSpace(input)
+	if matches := fanslyURLRegex.FindStringSubmatch(input); len(matches) > 1 {
+		input = matches[1]
+	}
+	return strings.TrimPrefix(input, "@")
+}
+
+// creatorNotFoundError is returned when a name matches no creator monitored in
+// the guild. Its message is shown to the member as is.
+type creatorNotFoundError struct {
+	Username   string
+	Suggestion string
+}
+
+func (e *creatorNotFoundError) Error() string {
+	message := fmt.Sprintf("**%s** isn't monitored in this server.", e.Username)
+	if e.Suggestion != "" {
+		message += fmt.Sprintf(" Did you mean **%s**?", e.Suggestion)
+	}
+	return message
+}
+
+// resolveCreator finds the guild's subscription for a creator given as a
+// username in any case, an account ID, a Fansly link or a username the
+// creator used before renaming.
+func (b *Bot) resolveCreator(guildID, input string) (*models.MonitoredUser, error) {
+	username := normalizeUsername(input)
+
+	users, err := b.Repo.GetMonitoredUsersForGuild(guildID)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range users {
+		if strings.EqualFold(users[idx].Username, username) || users[idx].UserID == username {
+			return &users[idx], nil
+		}
+	}
+
+	alias, err := b.Repo.GetCreatorAlias(username)
+	if err != nil {
+		log.Printf("Error looking up old username %s: %v", username, err)
+	} else if alias != nil {
+		for idx := range users {
+			if users[idx].UserID == alias.UserID {
+				return &users[idx], nil
+			}
+		}
+	}
+
+	return nil, &creatorNotFoundError{Username: username, Suggestion: closestUsername(username, users)}
+}
+
+// closestUsername suggests the monitored creator whose username is fewest
+// edits away, as long as it is close enough to be a likely typo.
+func closestUsername(username string, users []models.MonitoredUser) string {
+	maxDistance := max(2, len([]rune(username))/3)
+
+	best := ""
+	bestDistance := maxDistance + 1
+	for _, user := range users {
+		distance := editDistance(strings.ToLower(username), strings.ToLower(user.Username))
+		if distance < bestDistance {
+			best = user.Username
+			bestDistance = distance
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// creatorMiddleware resolves the "username" option of every command to the
+// stored username of a creator monitored in the guild, so handlers can match
+// it exactly. Commands that need an existing creator are stopped with a
+// suggestion when nothing matches; the others receive the normalized name.
+func (b *Bot) creatorMiddleware(cmd *command, next commandHandler) commandHandler {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+		if i.GuildID == "" || !opts.Has("username") {
+			next(s, i, opts)
+			return
+		}
+
+		username := normalizeUsername(opts.String("username"))
+		user, err := b.resolveCreator(i.GuildID, username)
+		switch {
+		case err == nil:
+			username = user.Username
+		case cmd.NewCreator:
+		default:
+			message := err.Error()
+			if _, ok := err.(*creatorNotFoundError); !ok {
+				log.Printf("Error resolving creator %q in guild %s: %v", username, i.GuildID, err)
+				message = "An error occurred. Please try again later."
+			}
+			b.editInteractionResponse(s, i, message)
+			return
+		}
+
+		option := *opts["username"]
+		option.Value = username
+		opts["username"] = &option
+		next(s, i, opts)
+	}
+}
@@ -0,0 +1,157 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/events"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+const (
+	// Discord requires external events to start in the future and to have an
+	// end time. The event is started right away and completed when the stream
+	// ends, so the end time only matters if the bot is offline at that moment.
+	scheduledEventStartDelay = 10 * time.Second
+	scheduledEventMaxLength  = 12 * time.Hour
+)
+
+func (b *Bot) startScheduledEvents(e events.StreamStarted) {
+	for _, user := range e.Subscriptions {
+		settings, err := b.Repo.GetGuildSettings(user.GuildID)
+		if err != nil {
+			log.Printf("Error fetching settings for guild %s: %v", user.GuildID, err)
+			continue
+		}
+		if !settings.ScheduledEventsEnabled {
+			continue
+		}
+
+		// A previous event can be left over if the bot missed the end of the last stream.
+		b.endScheduledEvent(user.GuildID, user.UserID)
+
+		if err := b.createScheduledEvent(user); err != nil {
+			log.Printf("Error creating scheduled event for %s in guild %s: %v", user.Username, user.GuildID, err)
+		}
+	}
+}
+
+func (b *Bot) endScheduledEvents(e events.StreamEnded) {
+	for _, user := range e.Subscriptions {
+		b.endScheduledEvent(user.GuildID, user.UserID)
+	}
+}
+
+func (b *Bot) createScheduledEvent(user models.MonitoredUser) error {
+	start := b.Clock.Now().Add(scheduledEventStartDelay)
+	end := start.Add(scheduledEventMaxLength)
+	liveURL := fmt.Sprintf("https://fansly.com/live/%s", user.Username)
+
+	event, err := b.Discord.GuildScheduledEventCreate(user.GuildID, &discordgo.GuildScheduledEventParams{
+		Name:               fmt.Sprintf("%s is live on Fansly", user.Username),
+		Description:        fmt.Sprintf("%s is streaming now. Watch at %s", user.Username, liveURL),
+		ScheduledStartTime: &start,
+		ScheduledEndTime:   &end,
+		PrivacyLevel:       discordgo.GuildScheduledEventPrivacyLevelGuildOnly,
+		EntityType:         discordgo.GuildScheduledEventEntityTypeExternal,
+		EntityMetadata:     &discordgo.GuildScheduledEventEntityMetadata{Location: liveURL},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = b.Repo.SaveLiveEvent(&models.LiveEvent{
+		GuildID:   user.GuildID,
+		UserID:    user.UserID,
+		EventID:   event.ID,
+		CreatedAt: b.Clock.Now().Unix(),
+	})
+	if err != nil {
+		log.Printf("Error storing scheduled event %s for guild %s: %v", event.ID, user.GuildID, err)
+	}
+
+	_, err = b.Discord.GuildScheduledEventEdit(user.GuildID, event.ID, &discordgo.GuildScheduledEventParams{
+		Status: discordgo.GuildScheduledEventStatusActive,
+	})
+	if err != nil {
+		return fmt.Errorf("event created but could not be started: %w", err)
+	}
+	return nil
+}
+
+// endScheduledEvent completes the stored event for a creator, or cancels it
+// if it never started, and forgets it.
+func (b *Bot) endScheduledEvent(guildID, userID string) {
+	liveEvent, err := b.Repo.GetLiveEvent(guildID, userID)
+	if err != nil {
+		log.Printf("Error fetching scheduled event for guild %s: %v", guildID, err)
+		return
+	}
+	if liveEvent == nil {
+		return
+	}
+
+	event, err := b.Discord.GuildScheduledEvent(guildID, liveEvent.EventID, false)
+	if err == nil {
+		status := discordgo.GuildScheduledEventStatusCompleted
+		if event.Status == discordgo.GuildScheduledEventStatusScheduled {
+			status = discordgo.GuildScheduledEventStatusCanceled
+		}
+		if event.Status == discordgo.GuildScheduledEventStatusScheduled || event.Status == discordgo.GuildScheduledEventStatusActive {
+			_, err = b.Discord.GuildScheduledEventEdit(guildID, liveEvent.EventID, &discordgo.GuildScheduledEventParams{Status: status})
+		}
+	}
+	if err != nil && discordErrorCode(err) != discordgo.ErrCodeUnknownGuildScheduledEvent {
+		log.Printf("Error ending scheduled event %s in guild %s: %v", liveEvent.EventID, guildID, err)
+	}
+
+	if err := b.Repo.DeleteLiveEvent(guildID, userID); err != nil {
+		log.Printf("Error deleting scheduled event record for guild %s: %v", guildID, err)
+	}
+}
+
+// cleanupScheduledEvents ends events whose stream finished while the bot was offline.
+func (b *Bot) cleanupScheduledEvents() {
+	liveEvents, err := b.Repo.GetLiveEvents()
+	if err != nil {
+		log.Printf("Error fetching scheduled events for cleanup: %v", err)
+		return
+	}
+
+	for _, liveEvent := range liveEvents {
+		user, err := b.Repo.GetMonitoredUser(liveEvent.GuildID, liveEvent.UserID)
+		if err != nil {
+			log.Printf("Error fetching subscription for scheduled event %s: %v", liveEvent.EventID, err)
+			continue
+		}
+		if user != nil && user.IsLive {
+			continue
+		}
+		log.Printf("Ending stale scheduled event %s in guild %s", liveEvent.EventID, liveEvent.GuildID)
+		b.endScheduledEvent(liveEvent.GuildID, liveEvent.UserID)
+	}
+}
+
+func (b *Bot) handleLiveEventsCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	enabled := opts.Bool("enabled")
+
+	settings, err := b.Repo.GetGuildSettings(i.GuildID)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching server settings: %v", err))
+		return
+	}
+
+	settings.ScheduledEventsEnabled = enabled
+	if err := b.Repo.SaveGuildSettings(settings); err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error saving server settings: %v", err))
+		return
+	}
+
+	if !enabled {
+		b.editInteractionResponse(s, i, "Live streams will no longer be mirrored as server events.")
+		return
+	}
+	b.editInteractionResponse(s, i, "Live streams will now appear as server events while the creator is live. The bot needs the **Manage Events** permission.")
+}
@@ -0,0 +1,20 @@
+package bot
+
+import "time"
+
+const schedulerInterval = time.Minute
+
+// runScheduler drives time based work: digests, notifications released after
+// quiet hours, expired mutes and the data of guilds the bot left.
+func (b *Bot) runScheduler() {
+	ticker := time.NewTicker(schedulerInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		now := b.Clock.Now()
+		b.flushDueDigests(now)
+		b.releaseQueuedNotifications(now)
+		b.clearExpiredMutes(now)
+		b.purgeExpiredGuilds(now)
+	}
+}
@@ -0,0 +1,222 @@
+package bot
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/sink"
+)
+
+const (
+	maxWebhookEndpointsPerGuild = 5
+	sinkDeliveryTimeout         = 2 * time.Minute
+)
+
+// publishEvent delivers a payload to every endpoint registered for its guild.
+// Payloads with an empty GuildID go to the owner's global endpoints.
+// Delivery happens in the background so slow endpoints never hold up monitoring.
+func (b *Bot) publishEvent(payload sink.Payload) {
+	go func() {
+		endpoints, err := b.Repo.GetWebhookEndpointsForGuild(payload.GuildID)
+		if err != nil {
+			log.Printf("Error fetching webhook endpoints for guild %q: %v", payload.GuildID, err)
+			return
+		}
+		for _, endpoint := range endpoints {
+			b.deliverToEndpoint(endpoint, payload)
+		}
+	}()
+}
+
+func (b *Bot) deliverToEndpoint(endpoint models.WebhookEndpoint, payload sink.Payload) error {
+	ctx, cancel := context.WithTimeout(context.Background(), sinkDeliveryTimeout)
+	defer cancel()
+
+	// Only the bot owner can register global endpoints, so those may point at private hosts.
+	s := sink.NewHTTPSink(endpoint.URL, endpoint.Secret, endpoint.GuildID == "")
+	err := s.Send(ctx, payload)
+	if err == nil {
+		return nil
+	}
+
+	log.Printf("Error delivering %s event to webhook endpoint %d: %v", payload.Event, endpoint.ID, err)
+
+	attempts := 1
+	if deliveryErr, ok := err.(*sink.DeliveryError); ok {
+		attempts = deliveryErr.Attempts
+	}
+	body, _ := json.Marshal(payload)
+	deadLetterErr := b.Repo.AddWebhookDeadLetter(&models.WebhookDeadLetter{
+		EndpointID: endpoint.ID,
+		URL:        endpoint.URL,
+		Event:      payload.Event,
+		Payload:    string(body),
+		Error:      err.Error(),
+		Attempts:   attempts,
+		CreatedAt:  b.Clock.Now().Unix(),
+	})
+	if deadLetterErr != nil {
+		log.Printf("Error recording dead letter for webhook endpoint %d: %v", endpoint.ID, deadLetterErr)
+	}
+	return err
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// webhookScope returns the guild ID endpoints are stored under. The owner can
+// manage global endpoints, which are stored with an empty guild ID.
+func (b *Bot) webhookScope(i *discordgo.InteractionCreate, opts commandOptions) (string, bool) {
+	if opts.Bool("global") {
+		return "", b.isBotOwner(i)
+	}
+	return i.GuildID, true
+}
+
+func (b *Bot) handleWebhookAdd(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	guildID, allowed := b.webhookScope(i, opts)
+	if !allowed {
+		b.editInteractionResponse(s, i, "Only the bot owner can register global endpoints.")
+		return
+	}
+
+	url := strings.TrimSpace(opts.String("url"))
+	if err := sink.ValidateURL(url, guildID == ""); err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error: %v", err))
+		return
+	}
+
+	if guildID != "" {
+		count, err := b.Repo.CountWebhookEndpointsForGuild(guildID)
+		if err != nil {
+			b.editInteractionResponse(s, i, fmt.Sprintf("Error checking endpoint limit: %v", err))
+			return
+		}
+		if count >= maxWebhookEndpointsPerGuild {
+			b.editInteractionResponse(s, i, fmt.Sprintf("This server already has %d webhook endpoints. Remove one with `/webhook remove` first.", maxWebhookEndpointsPerGuild))
+			return
+		}
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error generating secret: %v", err))
+		return
+	}
+
+	endpoint := &models.WebhookEndpoint{
+		GuildID:   guildID,
+		URL:       url,
+		Secret:    secret,
+		CreatedBy: interactionUserID(i),
+		CreatedAt: b.Clock.Now().Unix(),
+	}
+	if err := b.Repo.AddWebhookEndpoint(endpoint); err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error storing endpoint: %v", err))
+		return
+	}
+
+	b.editInteractionResponse(s, i, fmt.Sprintf(
+		"✅ Registered endpoint **#%d** for `%s`.\n\n"+
+			"Signing secret (shown only once):\n`%s`\n\n"+
+			"Each request carries `%s`, `%s` and `%s: sha256=<hex>`, where the signature is the HMAC-SHA256 of `<timestamp>.<body>` using the secret.",
+		endpoint.ID, url, secret, sink.HeaderEvent, sink.HeaderTimestamp, sink.HeaderSignature,
+	))
+}
+
+func (b *Bot) handleWebhookRemove(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	guildID, allowed := b.webhookScope(i, opts)
+	if !allowed {
+		b.editInteractionResponse(s, i, "Only the bot owner can manage global endpoints.")
+		return
+	}
+
+	id := uint(opts.Int("id"))
+	err := b.Repo.DeleteWebhookEndpoint(guildID, id)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error removing endpoint: %v", err))
+		return
+	}
+
+	b.editInteractionResponse(s, i, fmt.Sprintf("Removed webhook endpoint **#%d**.", id))
+}
+
+func (b *Bot) handleWebhookTest(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	guildID, allowed := b.webhookScope(i, opts)
+	if !allowed {
+		b.editInteractionResponse(s, i, "Only the bot owner can manage global endpoints.")
+		return
+	}
+
+	id := uint(opts.Int("id"))
+	endpoint, err := b.Repo.GetWebhookEndpoint(guildID, id)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching endpoint: %v", err))
+		return
+	}
+	if endpoint == nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("Webhook endpoint **#%d** was not found.", id))
+		return
+	}
+
+	payload := sink.Payload{
+		Version:   sink.PayloadVersion,
+		Event:     sink.EventTest,
+		Timestamp: b.Clock.Now().Unix(),
+		GuildID:   guildID,
+		Creator: sink.Creator{
+			ID:         "0",
+			Username:   "example",
+			ProfileURL: "https://fansly.com/example",
+		},
+	}
+
+	if err := b.deliverToEndpoint(*endpoint, payload); err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("❌ Test delivery to **#%d** failed: %v", id, err))
+		return
+	}
+	b.editInteractionResponse(s, i, fmt.Sprintf("✅ Test event delivered to **#%d**.", id))
+}
+
+func (b *Bot) handleWebhookList(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	scopes := []string{i.GuildID}
+	if b.isBotOwner(i) {
+		scopes = append(scopes, "")
+	}
+
+	var lines []string
+	for _, guildID := range scopes {
+		endpoints, err := b.Repo.GetWebhookEndpointsForGuild(guildID)
+		if err != nil {
+			b.editInteractionResponse(s, i, fmt.Sprintf("Error fetching endpoints: %v", err))
+			return
+		}
+		for _, endpoint := range endpoints {
+			failed, _ := b.Repo.CountWebhookDeadLetters(endpoint.ID)
+			scope := ""
+			if guildID == "" {
+				scope = " (global)"
+			}
+			lines = append(lines, fmt.Sprintf("**#%d**%s `%s` — %d failed deliveries", endpoint.ID, scope, endpoint.URL, failed))
+		}
+	}
+
+	if len(lines) == 0 {
+		b.editInteractionResponse(s, i, "No webhook endpoints are registered. Add one with `/webhook add`.")
+		return
+	}
+	b.editInteractionResponse(s, i, strings.Join(lines, "\n"))
+}
@@ -0,0 +1,209 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fvckgrimm/discord-fansly-notify/api"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/embed"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/events"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/sink"
+)
+
+// registerSubscribers wires everything that reacts to monitoring events.
+// History and metrics run first so they see every event even if delivery fails.
+func (b *Bot) registerSubscribers() {
+	b.Events.Subscribe(b.logEventHistory)
+	b.Events.Subscribe(b.metrics.record)
+
+	events.On(b.Events, b.deliverPostToDiscord)
+	events.On(b.Events, b.deliverStreamToDiscord)
+
+	events.On(b.Events, b.deliverPostToSubscribers)
+	events.On(b.Events, b.deliverStreamToSubscribers)
+
+	events.On(b.Events, b.announceRename)
+
+	events.On(b.Events, b.startScheduledEvents)
+	events.On(b.Events, b.endScheduledEvents)
+
+	events.On(b.Events, b.publishPostToSinks)
+	events.On(b.Events, b.publishStreamStartToSinks)
+	events.On(b.Events, b.publishStreamEndToSinks)
+}
+
+func (b *Bot) logEventHistory(event events.Event) {
+	switch e := event.(type) {
+	case events.PostPublished:
+		log.Printf("[event] %s: creator %s post %s for %d guilds", e.Name(), e.CreatorID, e.Post.ID, len(e.Subscriptions))
+	case events.StreamStarted:
+		log.Printf("[event] %s: creator %s started at %d for %d guilds", e.Name(), e.CreatorID, e.Stream.Response.Stream.StartedAt, len(e.Subscriptions))
+	case events.StreamEnded:
+		log.Printf("[event] %s: creator %s stream from %d for %d guilds", e.Name(), e.CreatorID, e.StartedAt, len(e.Subscriptions))
+	case events.AvatarChanged:
+		log.Printf("[event] %s: creator %s", e.Name(), e.CreatorID)
+	case events.UsernameChanged:
+		log.Printf("[event] %s: creator %s renamed from %s to %s", e.Name(), e.CreatorID, e.OldUsername, e.NewUsername)
+	default:
+		log.Printf("[event] %s", event.Name())
+	}
+}
+
+func (b *Bot) deliverPostToDiscord(e events.PostPublished) {
+	for _, user := range e.Subscriptions {
+		// Digest subscriptions collect posts and are summarised by the digest scheduler.
+		if user.DeliveryMode() != models.DeliveryInstant {
+			if isMuted(user, b.Clock.Now()) {
+				continue
+			}
+			if err := b.queueDigestPost(user, e.Post); err != nil {
+				log.Printf("Error queueing digest post for %s in guild %s: %v", user.Username, user.GuildID, err)
+			}
+			continue
+		}
+
+		// This flag is still useful for logging, but we won't use it to suppress the ping.
+		isFirstPostForThisServer := user.LastPostID == "" || user.LastPostID == "0"
+
+		log.Printf("Sending post notification for %s to guild %s. First post: %t", user.Username, user.GuildID, isFirstPostForThisServer)
+
+		if err := b.deliverNotification(newPostNotification(user, e.Post)); err != nil {
+			log.Printf("Post notification for %s in guild %s was not delivered: %v", user.Username, user.GuildID, err)
+		}
+	}
+}
+
+func (b *Bot) deliverStreamToDiscord(e events.StreamStarted) {
+	for _, user := range e.Subscriptions {
+		if err := b.deliverNotification(newStreamNotification(user, e.Stream)); err != nil {
+			log.Printf("Live notification for %s in guild %s was not delivered: %v", user.Username, user.GuildID, err)
+		}
+	}
+}
+
+// newPostNotification builds the notification for a post in the subscription's post channel.
+func newPostNotification(user models.MonitoredUser, post api.Post) notification {
+	// Pass nil for postMedia, as we are no longer fetching it.
+	embedMsg := embed.CreatePostEmbed(user.Username, post, user.AvatarLocation, nil)
+
+	// If a role is set, create the mention string. Otherwise, it's empty.
+	var mention string
+	if user.PostMentionRole != "" {
+		mention = fmt.Sprintf("<@&%s>", user.PostMentionRole)
+	}
+
+	targetChannel := user.PostNotificationChannel
+	if targetChannel == "" {
+		targetChannel = user.NotificationChannel
+	}
+
+	return notification{
+		Kind:    kindPost,
+		User:    user,
+		Channel: targetChannel,
+		Mention: mention,
+		Embed:   embedMsg,
+	}
+}
+
+// newStreamNotification builds the notification for a stream in the subscription's live channel.
+func newStreamNotification(user models.MonitoredUser, stream *api.StreamResponse) notification {
+	embedMsg := embed.CreateLiveStreamEmbed(user.Username, stream, user.AvatarLocation, user.LiveImageURL)
+
+	// If a role is set, create the mention string. Otherwise, it's empty.
+	var mention string
+	if user.LiveMentionRole != "" {
+		mention = fmt.Sprintf("<@&%s>", user.LiveMentionRole)
+	}
+
+	targetChannel := user.LiveNotificationChannel
+	if targetChannel == "" {
+		targetChannel = user.NotificationChannel
+	}
+
+	return notification{
+		Kind:    kindLive,
+		User:    user,
+		Channel: targetChannel,
+		Mention: mention,
+		Embed:   embedMsg,
+	}
+}
+
+// Outbound webhooks receive one event per guild plus a single copy for the owner's global endpoints.
+
+// globalSubscription keeps only the creator's own fields of a subscription,
+// so the copy for global endpoints carries nothing of the guild it came from.
+func globalSubscription(user models.MonitoredUser) models.MonitoredUser {
+	return models.MonitoredUser{
+		UserID:         user.UserID,
+		Username:       user.Username,
+		AvatarLocation: user.AvatarLocation,
+	}
+}
+
+func (b *Bot) publishPostToSinks(e events.PostPublished) {
+	now := b.Clock.Now().Unix()
+	b.publishEvent(sink.NewPostPayload("", globalSubscription(e.Subscriptions[0]), e.Post, now))
+	for _, user := range e.Subscriptions {
+		b.publishEvent(sink.NewPostPayload(user.GuildID, user, e.Post, now))
+	}
+}
+
+func (b *Bot) publishStreamStartToSinks(e events.StreamStarted) {
+	now := b.Clock.Now().Unix()
+	b.publishEvent(sink.NewStreamPayload("", globalSubscription(e.Subscriptions[0]), e.Stream, now))
+	for _, user := range e.Subscriptions {
+		b.publishEvent(sink.NewStreamPayload(user.GuildID, user, e.Stream, now))
+	}
+}
+
+func (b *Bot) publishStreamEndToSinks(e events.StreamEnded) {
+	now := b.Clock.Now().Unix()
+	b.publishEvent(sink.NewStreamEndedPayload("", globalSubscription(e.Subscriptions[0]), e.StartedAt, now))
+	for _, user := range e.Subscriptions {
+		b.publishEvent(sink.NewStreamEndedPayload(user.GuildID, user, e.StartedAt, now))
+	}
+}
+
+// eventMetrics counts published events by name.
+type eventMetrics struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+func newEventMetrics() *eventMetrics {
+	return &eventMetrics{counts: make(map[string]int64)}
+}
+
+func (m *eventMetrics) record(event events.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts[event.Name()]++
+}
+
+// summary renders the counters as "name=count" pairs sorted by name.
+func (m *eventMetrics) summary() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.counts) == 0 {
+		return "no events yet"
+	}
+
+	names := make([]string, 0, len(m.counts))
+	for name := range m.counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%d", name, m.counts[name]))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,75 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fvckgrimm/discord-fansly-notify/api"
+)
+
+const testNotificationFooter = "Test notification sent with /test"
+
+// handleTestCommand sends a sample notification for a subscription exactly as
+// a real one would be sent, after checking the bot's permissions in the
+// target channel. Mutes and quiet hours are ignored.
+func (b *Bot) handleTestCommand(s *discordgo.Session, i *discordgo.InteractionCreate, opts commandOptions) {
+	username := opts.String("username")
+	user, err := b.Repo.GetMonitoredUserByUsername(i.GuildID, username)
+	if err != nil || user == nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("**%s** isn't monitored in this server.", username))
+		return
+	}
+
+	now := b.Clock.Now()
+	var n notification
+	var mentionRole string
+	if opts.String("type") == "live" {
+		stream := &api.StreamResponse{Success: true}
+		stream.Response.Stream.Status = 2
+		stream.Response.Stream.StartedAt = now.UnixMilli()
+		n = newStreamNotification(*user, stream)
+		mentionRole = user.LiveMentionRole
+	} else {
+		n = newPostNotification(*user, api.Post{
+			ID:        user.LastPostID,
+			Content:   fmt.Sprintf("This is a sample post notification for %s. Real posts will look like this.", user.Username),
+			CreatedAt: now.Unix(),
+		})
+		mentionRole = user.PostMentionRole
+		if user.LastPostID == "" {
+			// No post seen yet, so link the profile instead of an empty post URL.
+			n.Embed.URL = n.Embed.Author.URL
+		}
+	}
+	n.Mention = joinMentions(n.Mention, notifyRoleMention(n.User))
+	n.Embed.Footer = &discordgo.MessageEmbedFooter{Text: testNotificationFooter}
+
+	channel, err := b.channelInfo(n.Channel)
+	if err != nil {
+		b.editInteractionResponse(s, i, fmt.Sprintf("❌ I can't access the %s channel <#%s>. Check that it still exists and that I can view it.", n.Kind, n.Channel))
+		return
+	}
+
+	var report []string
+	problem, deliverable := b.validateNotificationChannel(channel, n.User, mentionRole)
+	if problem != "" {
+		report = append(report, "⚠️ "+problem)
+	}
+	if !deliverable {
+		report = append(report, "❌ The test notification was not sent because notifications can't be delivered without these permissions.")
+		b.editInteractionResponse(s, i, strings.Join(report, "\n"))
+		return
+	}
+
+	msg, err := b.postNotification(n, channel)
+	if err != nil {
+		report = append(report, fmt.Sprintf("❌ Sending the test notification failed: %v", err))
+		b.editInteractionResponse(s, i, strings.Join(report, "\n"))
+		return
+	}
+
+	report = append(report, fmt.Sprintf("✅ Sent a test %s notification for **%s**: https://discord.com/channels/%s/%s/%s",
+		n.Kind, user.Username, i.GuildID, msg.ChannelID, msg.ID))
+	b.editInteractionResponse(s, i, strings.Join(report, "\n"))
+}
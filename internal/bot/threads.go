@@ -0,0 +1,119 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	threadArchiveMinutes = 1440
+	maxThreadNameLength  = 100
+	maxForumTagLength    = 20
+	maxForumTags         = 20
+)
+
+// notificationChannelTypes are the channel types notifications can be sent to.
+var notificationChannelTypes = []discordgo.ChannelType{
+	discordgo.ChannelTypeGuildText,
+	discordgo.ChannelTypeGuildNews,
+	discordgo.ChannelTypeGuildForum,
+	discordgo.ChannelTypeGuildPublicThread,
+	discordgo.ChannelTypeGuildPrivateThread,
+	discordgo.ChannelTypeGuildNewsThread,
+}
+
+func isSupportedNotificationChannel(channel *discordgo.Channel) bool {
+	for _, channelType := range notificationChannelTypes {
+		if channel.Type == channelType {
+			return true
+		}
+	}
+	return false
+}
+
+// startNotificationThread opens a discussion thread on a notification message
+// when the subscription asks for one. Messages already inside a thread or
+// forum post are left alone.
+func (b *Bot) startNotificationThread(n notification, channel *discordgo.Channel, msg *discordgo.Message) {
+	if msg == nil || channel.IsThread() || channel.Type == discordgo.ChannelTypeGuildForum {
+		return
+	}
+
+	wanted := n.User.ThreadPosts
+	if n.Kind == kindLive {
+		wanted = n.User.ThreadLive
+	}
+	if !wanted {
+		return
+	}
+
+	_, err := b.Discord.MessageThreadStartComplex(channel.ID, msg.ID, &discordgo.ThreadStart{
+		Name:                notificationThreadName(n),
+		AutoArchiveDuration: threadArchiveMinutes,
+	})
+	if err != nil {
+		log.Printf("Error starting thread for %s notification of %s in channel %s: %v", n.Kind, n.User.Username, channel.ID, err)
+	}
+}
+
+func notificationThreadName(n notification) string {
+	var name string
+	switch n.Kind {
+	case kindLive:
+		name = fmt.Sprintf("%s is live", n.User.Username)
+	default:
+		title := ""
+		if n.Embed != nil {
+			title = strings.TrimSpace(strings.SplitN(n.Embed.Description, "\n", 2)[0])
+			if title == "" || n.Kind == kindDigest {
+				title = n.Embed.Title
+			}
+		}
+		name = fmt.Sprintf("%s: %s", n.User.Username, title)
+		if title == "" {
+			name = fmt.Sprintf("New post from %s", n.User.Username)
+		}
+	}
+
+	if runes := []rune(name); len(runes) > maxThreadNameLength {
+		name = string(runes[:maxThreadNameLength-1]) + "…"
+	}
+	return name
+}
+
+// forumCreatorTags returns the forum tag for a creator, creating it when the
+// forum does not have one yet. Tagging is best effort: without Manage Channels
+// or with the tag limit reached the post is simply created untagged.
+func (b *Bot) forumCreatorTags(forum *discordgo.Channel, username string) []string {
+	tagName := username
+	if runes := []rune(tagName); len(runes) > maxForumTagLength {
+		tagName = string(runes[:maxForumTagLength])
+	}
+
+	for _, tag := range forum.AvailableTags {
+		if strings.EqualFold(tag.Name, tagName) {
+			return []string{tag.ID}
+		}
+	}
+
+	if len(forum.AvailableTags) >= maxForumTags {
+		return nil
+	}
+
+	tags := append(append([]discordgo.ForumTag{}, forum.AvailableTags...), discordgo.ForumTag{Name: tagName})
+	updated, err := b.Discord.ChannelEditComplex(forum.ID, &discordgo.ChannelEdit{AvailableTags: &tags})
+	if err != nil {
+		log.Printf("Could not create forum tag %q in channel %s: %v", tagName, forum.ID, err)
+		return nil
+	}
+
+	for _, tag := range updated.AvailableTags {
+		if tag.Name == tagName {
+			return []string{tag.ID}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/fvckgrimm/discord-fansly-notify/internal/config"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+// suspendPosts pauses post notifications for subscriptions whose creator's
+// timeline the bot can no longer read, leaving live notifications on, and
+// tells each guild why.
+func (b *Bot) suspendPosts(users []models.MonitoredUser) {
+	for _, user := range users {
+		if err := b.Repo.UpdatePostsSuspended(user.GuildID, user.UserID, true); err != nil {
+			log.Printf("Error suspending post notifications for %s in guild %s: %v", user.Username, user.GuildID, err)
+			continue
+		}
+		log.Printf("Suspended post notifications for %s in guild %s: no timeline access", user.Username, user.GuildID)
+		b.sendNotice(user, "timeline access", fmt.Sprintf(
+			"⚠️ The bot's Fansly account can no longer see **%s**'s posts, so only live notifications will be sent for now. "+
+				"Post notifications turn back on by themselves once the account follows or subscribes to them again.", user.Username))
+	}
+}
+
+// resumePosts lifts the pause on suspended subscriptions and returns the ones
+// that were resumed.
+func (b *Bot) resumePosts(users []models.MonitoredUser) []models.MonitoredUser {
+	resumed := make([]models.MonitoredUser, 0, len(users))
+	for _, user := range users {
+		if err := b.Repo.UpdatePostsSuspended(user.GuildID, user.UserID, false); err != nil {
+			log.Printf("Error resuming post notifications for %s in guild %s: %v", user.Username, user.GuildID, err)
+			continue
+		}
+		log.Printf("Resumed post notifications for %s in guild %s", user.Username, user.GuildID)
+		b.sendNotice(user, "timeline access", fmt.Sprintf("✅ The bot can see **%s**'s posts again, so post notifications are back on.", user.Username))
+
+		user.PostsSuspended = false
+		resumed = append(resumed, user)
+	}
+	return resumed
+}
+
+// timelineProbeDue reports whether suspended subscriptions of a creator should
+// check the timeline again. Every timeline read counts, so a creator is probed
+// at most once per TimelineProbeMinutes.
+func (b *Bot) timelineProbeDue(creatorID string) bool {
+	health, checked := b.health.get(creatorID)
+	if !checked || health.TimelineCheckedAt == 0 {
+		return true
+	}
+	interval := time.Duration(config.TimelineProbeMinutes) * time.Minute
+	return b.Clock.Now().Sub(time.Unix(health.TimelineCheckedAt, 0)) >= interval
+}
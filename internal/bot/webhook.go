@@ -0,0 +1,107 @@
+package bot
+
+import (
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+const webhookName = "Fansly Notify"
+
+// sendViaWebhook posts a notification through the channel's webhook using the
+// creator's name and avatar. A webhook deleted from Discord is recreated once.
+// Threads use their parent channel's webhook and forums get a new post.
+func (b *Bot) sendViaWebhook(n notification, channel *discordgo.Channel) (*discordgo.Message, error) {
+	params := &discordgo.WebhookParams{
+		Content:    n.Mention,
+		Username:   n.User.Username,
+		AvatarURL:  n.User.AvatarLocation,
+		Embeds:     []*discordgo.MessageEmbed{n.Embed},
+		Components: notificationComponents(n),
+	}
+
+	webhookChannel := channel.ID
+	threadID := ""
+	if channel.IsThread() {
+		webhookChannel = channel.ParentID
+		threadID = channel.ID
+	}
+	if channel.Type == discordgo.ChannelTypeGuildForum {
+		params.ThreadName = notificationThreadName(n)
+	}
+
+	execute := func() (*discordgo.Message, error) {
+		webhook, err := b.channelWebhook(n.User.GuildID, webhookChannel)
+		if err != nil {
+			return nil, err
+		}
+		if threadID != "" {
+			return b.Discord.WebhookThreadExecute(webhook.WebhookID, webhook.Token, true, threadID, params)
+		}
+		return b.Discord.WebhookExecute(webhook.WebhookID, webhook.Token, true, params)
+	}
+
+	msg, err := execute()
+	if isUnknownWebhook(err) {
+		log.Printf("Webhook for channel %s was deleted, recreating it", webhookChannel)
+		if err := b.Repo.DeleteChannelWebhook(webhookChannel); err != nil {
+			return nil, err
+		}
+		msg, err = execute()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Webhooks cannot set forum tags when creating a post, so apply them afterwards.
+	if channel.Type == discordgo.ChannelTypeGuildForum {
+		if tags := b.forumCreatorTags(channel, n.User.Username); len(tags) > 0 {
+			if _, err := b.Discord.ChannelEditComplex(msg.ChannelID, &discordgo.ChannelEdit{AppliedTags: &tags}); err != nil {
+				log.Printf("Error tagging forum post in channel %s: %v", channel.ID, err)
+			}
+		}
+	}
+	return msg, nil
+}
+
+// channelWebhook returns the stored webhook for a channel, adopting an
+// existing one created by the bot or creating a new one if needed.
+func (b *Bot) channelWebhook(guildID, channelID string) (*models.ChannelWebhook, error) {
+	stored, err := b.Repo.GetChannelWebhook(channelID)
+	if err != nil || stored != nil {
+		return stored, err
+	}
+
+	var webhook *discordgo.Webhook
+	if existing, err := b.Discord.ChannelWebhooks(channelID); err == nil {
+		for _, w := range existing {
+			if w.Token != "" && w.User != nil && w.User.ID == b.Session.State.User.ID {
+				webhook = w
+				break
+			}
+		}
+	}
+
+	if webhook == nil {
+		webhook, err = b.Discord.WebhookCreate(channelID, webhookName, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	stored = &models.ChannelWebhook{
+		ChannelID: channelID,
+		GuildID:   guildID,
+		WebhookID: webhook.ID,
+		Token:     webhook.Token,
+	}
+	if err := b.Repo.SaveChannelWebhook(stored); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+func isUnknownWebhook(err error) bool {
+	return discordErrorCode(err) == discordgo.ErrCodeUnknownWebhook
+}
@@ -30,6 +30,11 @@ var (
 	AvatarRefreshIntervalHours  int
 	MonitorWorkerCount          int
 	MaxMonitoredUsersPerGuild   int
+	MaxSubscriptionsPerUser     int
+	GuildRetentionHours         int
+	TimelineProbeMinutes        int
+	FollowReconcileHours        int
+	UnfollowGraceHours          int
 
 	ApiRequestsPerSecond float64
 	ApiBurst             int
@@ -79,6 +84,11 @@ func Load() {
 	AvatarRefreshIntervalHours = getEnvAsInt("AVATAR_REFRESH_INTERVAL_HOURS", 144)   // Default: 6 days (6 * 24)
 	MonitorWorkerCount = getEnvAsInt("MONITOR_WORKER_COUNT", 10)                     // Default: 10 workers
 	MaxMonitoredUsersPerGuild = getEnvAsInt("MAX_MONITORED_USERS_PER_GUILD", 5)
+	MaxSubscriptionsPerUser = getEnvAsInt("MAX_SUBSCRIPTIONS_PER_USER", 10) // 0 disables the limit
+	GuildRetentionHours = getEnvAsInt("GUILD_RETENTION_HOURS", 72)          // 0 deletes data as soon as the bot is removed
+	TimelineProbeMinutes = getEnvAsInt("TIMELINE_PROBE_MINUTES", 60)        // How often paused post notifications check for timeline access
+	FollowReconcileHours = getEnvAsInt("FOLLOW_RECONCILE_HOURS", 24)        // 0 disables the periodic follow check
+	UnfollowGraceHours = getEnvAsInt("UNFOLLOW_GRACE_HOURS", 168)           // How long unmonitored creators stay followed
 
 	ApiRequestsPerSecond = getEnvAsFloat64("API_REQUESTS_PER_SECOND", 2.0)
 	ApiBurst = getEnvAsInt("API_BURST", 5)
@@ -0,0 +1,34 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+	"gorm.io/gorm"
+)
+
+// GetChannelWebhook returns the stored webhook for a channel, or nil if there is none
+func (r *Repository) GetChannelWebhook(channelID string) (*models.ChannelWebhook, error) {
+	var webhook models.ChannelWebhook
+	err := WithRetry(func() error {
+		return r.db.Where("channel_id = ?", channelID).First(&webhook).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &webhook, err
+}
+
+// SaveChannelWebhook creates or replaces the stored webhook for a channel
+func (r *Repository) SaveChannelWebhook(webhook *models.ChannelWebhook) error {
+	return WithRetry(func() error {
+		return r.db.Save(webhook).Error
+	})
+}
+
+// DeleteChannelWebhook forgets the stored webhook for a channel
+func (r *Repository) DeleteChannelWebhook(channelID string) error {
+	return WithRetry(func() error {
+		return r.db.Delete(&models.ChannelWebhook{}, "channel_id = ?", channelID).Error
+	})
+}
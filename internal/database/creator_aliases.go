@@ -0,0 +1,48 @@
+package database
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AddCreatorAlias records a username a creator used before, updating the
+// time it was replaced if it was already recorded
+func (r *Repository) AddCreatorAlias(alias *models.CreatorAlias) error {
+	return WithRetry(func() error {
+		return r.db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "username"}},
+			DoUpdates: clause.AssignmentColumns([]string{"replaced_at"}),
+		}).Create(alias).Error
+	})
+}
+
+// GetCreatorAlias returns the most recently replaced alias matching the
+// username, ignoring case, or nil if no creator used it
+func (r *Repository) GetCreatorAlias(username string) (*models.CreatorAlias, error) {
+	var alias models.CreatorAlias
+	err := WithRetry(func() error {
+		return r.db.Where("LOWER(username) = ?", strings.ToLower(username)).
+			Order("replaced_at DESC").
+			First(&alias).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &alias, nil
+}
+
+// GetCreatorAliases returns the usernames a creator used before, newest first
+func (r *Repository) GetCreatorAliases(userID string) ([]models.CreatorAlias, error) {
+	var aliases []models.CreatorAlias
+	err := WithRetry(func() error {
+		return r.db.Where("user_id = ?", userID).Order("replaced_at DESC").Find(&aliases).Error
+	})
+	return aliases, err
+}
@@ -0,0 +1,28 @@
+package database
+
+import (
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+// GetAllCreatorHealth returns the stored health summary of every creator
+func (r *Repository) GetAllCreatorHealth() ([]models.CreatorHealth, error) {
+	var health []models.CreatorHealth
+	err := WithRetry(func() error {
+		return r.db.Find(&health).Error
+	})
+	return health, err
+}
+
+// SaveCreatorHealth creates or replaces health summaries
+func (r *Repository) SaveCreatorHealth(health []models.CreatorHealth) error {
+	return WithRetry(func() error {
+		return r.db.Save(&health).Error
+	})
+}
+
+// DeleteCreatorHealth removes the health summary of a creator no guild monitors anymore
+func (r *Repository) DeleteCreatorHealth(creatorID string) error {
+	return WithRetry(func() error {
+		return r.db.Where("creator_id = ?", creatorID).Delete(&models.CreatorHealth{}).Error
+	})
+}
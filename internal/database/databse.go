@@ -17,7 +17,7 @@ import (
 	"gorm.io/gorm/logger"
 )
 
-const currentVersion = 3
+const currentVersion = 19
 
 var (
 	DB     *gorm.DB
@@ -91,7 +91,23 @@ func Init(dbType string, connString string) error {
 	}
 
 	// Auto-migrate models
-	err = DB.AutoMigrate(&models.SchemaVersion{}, &models.MonitoredUser{})
+	err = DB.AutoMigrate(
+		&models.SchemaVersion{},
+		&models.MonitoredUser{},
+		&models.GuildSettings{},
+		&models.PendingPost{},
+		&models.QueuedNotification{},
+		&models.ChannelWebhook{},
+		&models.WebhookEndpoint{},
+		&models.WebhookDeadLetter{},
+		&models.LiveEvent{},
+		&models.UserSubscription{},
+		&models.PermissionGrant{},
+		&models.CreatorHealth{},
+		&models.OrphanedGuild{},
+		&models.CreatorAlias{},
+		&models.UnmonitoredCreator{},
+	)
 	if err != nil {
 		return fmt.Errorf("failed to migrate database schema: %w", err)
 	}
@@ -137,6 +153,26 @@ func addIndexes() error {
 	if err != nil {
 		return err
 	}
+	err = DB.Exec("CREATE INDEX IF NOT EXISTS idx_pending_posts_guild_user ON pending_posts(guild_id, user_id)").Error
+	if err != nil {
+		return err
+	}
+	err = DB.Exec("CREATE INDEX IF NOT EXISTS idx_queued_notifications_release_at ON queued_notifications(release_at)").Error
+	if err != nil {
+		return err
+	}
+	err = DB.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_user_subscriptions_member_creator ON user_subscriptions(discord_user_id, guild_id, creator_id)").Error
+	if err != nil {
+		return err
+	}
+	err = DB.Exec("CREATE INDEX IF NOT EXISTS idx_user_subscriptions_creator ON user_subscriptions(creator_id)").Error
+	if err != nil {
+		return err
+	}
+	err = DB.Exec("CREATE INDEX IF NOT EXISTS idx_permission_grants_guild ON permission_grants(guild_id, target_id)").Error
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -154,6 +190,22 @@ func runMigrations(currentDBVersion int) error {
 		migrateToV1,
 		migrateToV2,
 		migrateToV3,
+		migrateToV4,
+		migrateToV5,
+		migrateToV6,
+		migrateToV7,
+		migrateToV8,
+		migrateToV9,
+		migrateToV10,
+		migrateToV11,
+		migrateToV12,
+		migrateToV13,
+		migrateToV14,
+		migrateToV15,
+		migrateToV16,
+		migrateToV17,
+		migrateToV18,
+		migrateToV19,
 		// Add new migrations here
 	}
 
@@ -195,6 +247,90 @@ func migrateToV3(db *gorm.DB) error {
 	return nil
 }
 
+func migrateToV4(db *gorm.DB) error {
+	// guild_settings, pending_posts and post_delivery_mode are created by AutoMigrate.
+	// An empty delivery mode is treated as instant, so no backfill is needed.
+	return nil
+}
+
+func migrateToV5(db *gorm.DB) error {
+	// Quiet hour settings, muted_until and queued_notifications are created by AutoMigrate.
+	return nil
+}
+
+func migrateToV6(db *gorm.DB) error {
+	// webhook_delivery and channel_webhooks are created by AutoMigrate.
+	return nil
+}
+
+func migrateToV7(db *gorm.DB) error {
+	// webhook_endpoints and webhook_dead_letters are created by AutoMigrate.
+	return nil
+}
+
+func migrateToV8(db *gorm.DB) error {
+	// is_live is created by AutoMigrate. Streams that were live during the
+	// upgrade simply won't produce a stream ended event.
+	return nil
+}
+
+func migrateToV9(db *gorm.DB) error {
+	// scheduled_events_enabled and live_events are created by AutoMigrate.
+	return nil
+}
+
+func migrateToV10(db *gorm.DB) error {
+	// thread_live and thread_posts are added by AutoMigrate. Threads are opt-in
+	// through /notify threads, so existing subscriptions keep them off.
+	return nil
+}
+
+func migrateToV11(db *gorm.DB) error {
+	// auto_crosspost is added by AutoMigrate. Publishing is opt-in through
+	// /notify crosspost, so existing subscriptions keep it off.
+	return nil
+}
+
+func migrateToV12(db *gorm.DB) error {
+	// notify_buttons and notify_role_id are added by AutoMigrate.
+	return nil
+}
+
+func migrateToV13(db *gorm.DB) error {
+	// user_subscriptions is created by AutoMigrate.
+	return nil
+}
+
+func migrateToV14(db *gorm.DB) error {
+	// permission_grants is created by AutoMigrate.
+	return nil
+}
+
+func migrateToV15(db *gorm.DB) error {
+	// creator_health and the last delivery error columns are created by AutoMigrate.
+	return nil
+}
+
+func migrateToV16(db *gorm.DB) error {
+	// orphaned_guilds is created by AutoMigrate.
+	return nil
+}
+
+func migrateToV17(db *gorm.DB) error {
+	// creator_aliases and the announce_renames column are created by AutoMigrate.
+	return nil
+}
+
+func migrateToV18(db *gorm.DB) error {
+	// The posts_suspended column is created by AutoMigrate.
+	return nil
+}
+
+func migrateToV19(db *gorm.DB) error {
+	// unmonitored_creators is created by AutoMigrate.
+	return nil
+}
+
 // WithRetry performs a database operation with retry logic for locked database
 func WithRetry(operation func() error) error {
 	maxRetries := 5
@@ -0,0 +1,36 @@
+package database
+
+import (
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+	"gorm.io/gorm"
+)
+
+// ImportGuildConfig saves imported subscriptions, settings and permission
+// grants in one transaction. Grants replace any existing grant for the same
+// target; a nil settings leaves the stored settings untouched.
+func (r *Repository) ImportGuildConfig(users []models.MonitoredUser, settings *models.GuildSettings, grants []models.PermissionGrant) error {
+	return WithRetry(func() error {
+		return r.db.Transaction(func(tx *gorm.DB) error {
+			for idx := range users {
+				if err := tx.Save(&users[idx]).Error; err != nil {
+					return err
+				}
+			}
+			if settings != nil {
+				if err := tx.Save(settings).Error; err != nil {
+					return err
+				}
+			}
+			for idx := range grants {
+				grant := grants[idx]
+				if err := tx.Delete(&models.PermissionGrant{}, "guild_id = ? AND target_id = ?", grant.GuildID, grant.TargetID).Error; err != nil {
+					return err
+				}
+				if err := tx.Create(&grant).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
@@ -0,0 +1,31 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+	"gorm.io/gorm"
+)
+
+// GetGuildSettings returns the settings for a guild, or the defaults if none have been stored
+func (r *Repository) GetGuildSettings(guildID string) (*models.GuildSettings, error) {
+	settings := models.GuildSettings{
+		GuildID:    guildID,
+		Timezone:   models.DefaultTimezone,
+		DigestHour: models.DefaultDigestHour,
+	}
+	err := WithRetry(func() error {
+		return r.db.Where("guild_id = ?", guildID).First(&settings).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &settings, nil
+	}
+	return &settings, err
+}
+
+// SaveGuildSettings creates or replaces the settings for a guild
+func (r *Repository) SaveGuildSettings(settings *models.GuildSettings) error {
+	return WithRetry(func() error {
+		return r.db.Save(settings).Error
+	})
+}
@@ -0,0 +1,42 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+	"gorm.io/gorm"
+)
+
+// SaveLiveEvent stores the scheduled event created for a creator's stream in a guild
+func (r *Repository) SaveLiveEvent(event *models.LiveEvent) error {
+	return WithRetry(func() error {
+		return r.db.Save(event).Error
+	})
+}
+
+// GetLiveEvent returns the stored scheduled event for a creator in a guild, or nil if there is none
+func (r *Repository) GetLiveEvent(guildID, userID string) (*models.LiveEvent, error) {
+	var event models.LiveEvent
+	err := WithRetry(func() error {
+		return r.db.Where("guild_id = ? AND user_id = ?", guildID, userID).First(&event).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &event, err
+}
+
+// GetLiveEvents returns every stored scheduled event
+func (r *Repository) GetLiveEvents() ([]models.LiveEvent, error) {
+	var liveEvents []models.LiveEvent
+	err := WithRetry(func() error {
+		return r.db.Find(&liveEvents).Error
+	})
+	return liveEvents, err
+}
+
+func (r *Repository) DeleteLiveEvent(guildID, userID string) error {
+	return WithRetry(func() error {
+		return r.db.Delete(&models.LiveEvent{}, "guild_id = ? AND user_id = ?", guildID, userID).Error
+	})
+}
@@ -0,0 +1,926 @@
+package database
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+// MemoryStore is a Store that keeps everything in memory. It mirrors the
+// behaviour of Repository, including auto-incremented IDs, created_at
+// defaults and the order rows are returned in, so the bot can be exercised
+// without a database.
+type MemoryStore struct {
+	mu sync.Mutex
+
+	users           []models.MonitoredUser
+	settings        map[string]models.GuildSettings
+	orphans         map[string]models.OrphanedGuild
+	aliases         []models.CreatorAlias
+	pendingPosts    []models.PendingPost
+	queued          []models.QueuedNotification
+	grants          []models.PermissionGrant
+	subscriptions   []models.UserSubscription
+	channelWebhooks map[string]models.ChannelWebhook
+	liveEvents      []models.LiveEvent
+	health          map[string]models.CreatorHealth
+	unmonitored     map[string]models.UnmonitoredCreator
+	endpoints       []models.WebhookEndpoint
+	deadLetters     []models.WebhookDeadLetter
+
+	// ids holds the last auto-increment ID handed out per table.
+	ids map[string]uint
+}
+
+// NewMemoryStore returns an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		settings:        make(map[string]models.GuildSettings),
+		orphans:         make(map[string]models.OrphanedGuild),
+		channelWebhooks: make(map[string]models.ChannelWebhook),
+		health:          make(map[string]models.CreatorHealth),
+		unmonitored:     make(map[string]models.UnmonitoredCreator),
+		ids:             make(map[string]uint),
+	}
+}
+
+func (s *MemoryStore) nextID(table string) uint {
+	s.ids[table]++
+	return s.ids[table]
+}
+
+// createdAt mimics GORM filling an unset CreatedAt column on insert.
+func createdAt(at int64) int64 {
+	if at == 0 {
+		return time.Now().Unix()
+	}
+	return at
+}
+
+// removeWhere drops the items matching the predicate and returns how many were removed.
+func removeWhere[T any](items *[]T, match func(T) bool) int {
+	before := len(*items)
+	*items = slices.DeleteFunc(*items, match)
+	return before - len(*items)
+}
+
+func filter[T any](items []T, match func(T) bool) []T {
+	var matched []T
+	for _, item := range items {
+		if match(item) {
+			matched = append(matched, item)
+		}
+	}
+	return matched
+}
+
+// Monitored users
+
+func (s *MemoryStore) userIndex(guildID, userID string) int {
+	return slices.IndexFunc(s.users, func(u models.MonitoredUser) bool {
+		return u.GuildID == guildID && u.UserID == userID
+	})
+}
+
+func (s *MemoryStore) saveUser(user models.MonitoredUser) {
+	if idx := s.userIndex(user.GuildID, user.UserID); idx >= 0 {
+		s.users[idx] = user
+		return
+	}
+	s.users = append(s.users, user)
+}
+
+// updateUser applies update to a subscription addressed by creator ID. Like an
+// SQL update, nothing happens when it does not exist.
+func (s *MemoryStore) updateUser(guildID, userID string, update func(*models.MonitoredUser)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if idx := s.userIndex(guildID, userID); idx >= 0 {
+		update(&s.users[idx])
+	}
+	return nil
+}
+
+// updateByUsername applies update to the subscriptions of a guild with the
+// username, returning ErrUserNotFound when there are none.
+func (s *MemoryStore) updateByUsername(guildID, username string, update func(*models.MonitoredUser)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	found := false
+	for idx := range s.users {
+		if s.users[idx].GuildID == guildID && s.users[idx].Username == username {
+			update(&s.users[idx])
+			found = true
+		}
+	}
+	if !found {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (s *MemoryStore) GetMonitoredUsers() ([]models.MonitoredUser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return filter(s.users, func(u models.MonitoredUser) bool {
+		_, orphaned := s.orphans[u.GuildID]
+		return !orphaned
+	}), nil
+}
+
+func (s *MemoryStore) GetMonitoredUser(guildID, userID string) (*models.MonitoredUser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if idx := s.userIndex(guildID, userID); idx >= 0 {
+		user := s.users[idx]
+		return &user, nil
+	}
+	return nil, nil
+}
+
+func (s *MemoryStore) GetMonitoredUserByUsername(guildID, username string) (*models.MonitoredUser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, user := range s.users {
+		if user.GuildID == guildID && user.Username == username {
+			return &user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *MemoryStore) GetMonitoredUsersForGuild(guildID string) ([]models.MonitoredUser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return filter(s.users, func(u models.MonitoredUser) bool { return u.GuildID == guildID }), nil
+}
+
+func (s *MemoryStore) CountMonitoredUsersForGuild(guildID string) (int64, error) {
+	users, _ := s.GetMonitoredUsersForGuild(guildID)
+	return int64(len(users)), nil
+}
+
+func (s *MemoryStore) CountMonitoredUsers() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return int64(len(s.users)), nil
+}
+
+func (s *MemoryStore) CountGuilds() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	guilds := make(map[string]bool)
+	for _, user := range s.users {
+		guilds[user.GuildID] = true
+	}
+	return int64(len(guilds)), nil
+}
+
+func (s *MemoryStore) AddMonitoredUser(user *models.MonitoredUser) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.userIndex(user.GuildID, user.UserID) >= 0 {
+		return fmt.Errorf("monitored user %s already exists in guild %s", user.UserID, user.GuildID)
+	}
+	s.users = append(s.users, *user)
+	return nil
+}
+
+// AddOrUpdateMonitoredUser only overwrites the columns Repository updates on conflict.
+func (s *MemoryStore) AddOrUpdateMonitoredUser(user *models.MonitoredUser) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	idx := s.userIndex(user.GuildID, user.UserID)
+	if idx < 0 {
+		s.users = append(s.users, *user)
+		return nil
+	}
+
+	existing := &s.users[idx]
+	existing.Username = user.Username
+	existing.NotificationChannel = user.NotificationChannel
+	existing.PostNotificationChannel = user.PostNotificationChannel
+	existing.LiveNotificationChannel = user.LiveNotificationChannel
+	existing.LastPostID = user.LastPostID
+	existing.LastStreamStart = user.LastStreamStart
+	existing.MentionRole = user.MentionRole
+	existing.AvatarLocation = user.AvatarLocation
+	existing.AvatarLocationUpdatedAt = user.AvatarLocationUpdatedAt
+	existing.LiveImageURL = user.LiveImageURL
+	existing.PostsEnabled = user.PostsEnabled
+	existing.LiveEnabled = user.LiveEnabled
+	existing.LiveMentionRole = user.LiveMentionRole
+	existing.PostMentionRole = user.PostMentionRole
+	existing.PostDeliveryMode = user.PostDeliveryMode
+	existing.MutedUntil = user.MutedUntil
+	existing.WebhookDelivery = user.WebhookDelivery
+	existing.ThreadLive = user.ThreadLive
+	existing.ThreadPosts = user.ThreadPosts
+	existing.AutoCrosspost = user.AutoCrosspost
+	existing.NotifyButtons = user.NotifyButtons
+	existing.LastDeliveryError = user.LastDeliveryError
+	existing.LastDeliveryErrorAt = user.LastDeliveryErrorAt
+	existing.PostsSuspended = user.PostsSuspended
+	return nil
+}
+
+func (s *MemoryStore) UpdateMonitoredUser(user *models.MonitoredUser) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saveUser(*user)
+	return nil
+}
+
+func (s *MemoryStore) DeleteMonitoredUser(guildID, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removeWhere(&s.users, func(u models.MonitoredUser) bool { return u.GuildID == guildID && u.UserID == userID })
+	return nil
+}
+
+func (s *MemoryStore) DeleteMonitoredUserByUsername(guildID, username string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if removeWhere(&s.users, func(u models.MonitoredUser) bool { return u.GuildID == guildID && u.Username == username }) == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (s *MemoryStore) DeleteAllUsersInGuild(guildID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removeWhere(&s.users, func(u models.MonitoredUser) bool { return u.GuildID == guildID })
+	return nil
+}
+
+func (s *MemoryStore) UpdateLastPostID(guildID, userID, postID string) error {
+	return s.updateUser(guildID, userID, func(u *models.MonitoredUser) { u.LastPostID = postID })
+}
+
+func (s *MemoryStore) UpdateLastStreamStart(guildID, userID string, timestamp int64) error {
+	return s.updateUser(guildID, userID, func(u *models.MonitoredUser) { u.LastStreamStart = timestamp })
+}
+
+func (s *MemoryStore) UpdateIsLive(guildID, userID string, isLive bool) error {
+	return s.updateUser(guildID, userID, func(u *models.MonitoredUser) { u.IsLive = isLive })
+}
+
+func (s *MemoryStore) UpdateUsername(userID, username string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for idx := range s.users {
+		if s.users[idx].UserID == userID {
+			s.users[idx].Username = username
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) UpdateAvatarInfo(guildID, userID, avatarLocation string, updatedAt int64) error {
+	return s.updateUser(guildID, userID, func(u *models.MonitoredUser) {
+		u.AvatarLocation = avatarLocation
+		u.AvatarLocationUpdatedAt = updatedAt
+	})
+}
+
+func (s *MemoryStore) UpdateNotifyRoleID(guildID, userID, roleID string) error {
+	return s.updateUser(guildID, userID, func(u *models.MonitoredUser) { u.NotifyRoleID = roleID })
+}
+
+func (s *MemoryStore) UpdateDeliveryError(guildID, userID, message string, at int64) error {
+	return s.updateUser(guildID, userID, func(u *models.MonitoredUser) {
+		u.LastDeliveryError = message
+		u.LastDeliveryErrorAt = at
+	})
+}
+
+func (s *MemoryStore) UpdatePostsSuspended(guildID, userID string, suspended bool) error {
+	return s.updateUser(guildID, userID, func(u *models.MonitoredUser) { u.PostsSuspended = suspended })
+}
+
+func (s *MemoryStore) UpdateLastPostIDByUsername(guildID, username, postID string) error {
+	return s.updateByUsername(guildID, username, func(u *models.MonitoredUser) { u.LastPostID = postID })
+}
+
+func (s *MemoryStore) UpdateAvatarInfoByUsername(guildID, username, avatarLocation string) error {
+	return s.updateByUsername(guildID, username, func(u *models.MonitoredUser) {
+		u.AvatarLocation = avatarLocation
+		u.AvatarLocationUpdatedAt = time.Now().Unix()
+	})
+}
+
+func (s *MemoryStore) DisablePostsByUsername(guildID, username string) error {
+	return s.updateByUsername(guildID, username, func(u *models.MonitoredUser) {
+		u.PostsEnabled = false
+		u.PostsSuspended = false
+	})
+}
+
+func (s *MemoryStore) EnablePostsByUsername(guildID, username string) error {
+	return s.updateByUsername(guildID, username, func(u *models.MonitoredUser) {
+		u.PostsEnabled = true
+		u.PostsSuspended = false
+	})
+}
+
+func (s *MemoryStore) DisableLiveByUsername(guildID, username string) error {
+	return s.updateByUsername(guildID, username, func(u *models.MonitoredUser) { u.LiveEnabled = false })
+}
+
+func (s *MemoryStore) EnableLiveByUsername(guildID, username string) error {
+	return s.updateByUsername(guildID, username, func(u *models.MonitoredUser) { u.LiveEnabled = true })
+}
+
+func (s *MemoryStore) UpdateLiveImageURL(guildID, username, imageURL string) error {
+	return s.updateByUsername(guildID, username, func(u *models.MonitoredUser) { u.LiveImageURL = imageURL })
+}
+
+func (s *MemoryStore) UpdatePostChannel(guildID, username, channelID string) error {
+	return s.updateByUsername(guildID, username, func(u *models.MonitoredUser) { u.PostNotificationChannel = channelID })
+}
+
+func (s *MemoryStore) UpdateLiveChannel(guildID, username, channelID string) error {
+	return s.updateByUsername(guildID, username, func(u *models.MonitoredUser) { u.LiveNotificationChannel = channelID })
+}
+
+func (s *MemoryStore) UpdatePostMentionRole(guildID, username, roleID string) error {
+	return s.updateByUsername(guildID, username, func(u *models.MonitoredUser) { u.PostMentionRole = roleID })
+}
+
+func (s *MemoryStore) UpdateLiveMentionRole(guildID, username, roleID string) error {
+	return s.updateByUsername(guildID, username, func(u *models.MonitoredUser) { u.LiveMentionRole = roleID })
+}
+
+func (s *MemoryStore) UpdateDeliveryModeByUsername(guildID, username, mode string) error {
+	return s.updateByUsername(guildID, username, func(u *models.MonitoredUser) { u.PostDeliveryMode = mode })
+}
+
+func (s *MemoryStore) UpdateMutedUntilByUsername(guildID, username string, mutedUntil int64) error {
+	return s.updateByUsername(guildID, username, func(u *models.MonitoredUser) { u.MutedUntil = mutedUntil })
+}
+
+func (s *MemoryStore) UpdateWebhookDeliveryByUsername(guildID, username string, enabled bool) error {
+	return s.updateByUsername(guildID, username, func(u *models.MonitoredUser) { u.WebhookDelivery = enabled })
+}
+
+func (s *MemoryStore) UpdatePostThreadsByUsername(guildID, username string, enabled bool) error {
+	return s.updateByUsername(guildID, username, func(u *models.MonitoredUser) { u.ThreadPosts = enabled })
+}
+
+func (s *MemoryStore) UpdateLiveThreadsByUsername(guildID, username string, enabled bool) error {
+	return s.updateByUsername(guildID, username, func(u *models.MonitoredUser) { u.ThreadLive = enabled })
+}
+
+func (s *MemoryStore) UpdateAutoCrosspostByUsername(guildID, username string, enabled bool) error {
+	return s.updateByUsername(guildID, username, func(u *models.MonitoredUser) { u.AutoCrosspost = enabled })
+}
+
+func (s *MemoryStore) UpdateNotifyButtonsByUsername(guildID, username string, enabled bool) error {
+	return s.updateByUsername(guildID, username, func(u *models.MonitoredUser) { u.NotifyButtons = enabled })
+}
+
+func (s *MemoryStore) ClearExpiredMutes(now int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var cleared int64
+	for idx := range s.users {
+		if s.users[idx].MutedUntil > 0 && s.users[idx].MutedUntil <= now {
+			s.users[idx].MutedUntil = 0
+			cleared++
+		}
+	}
+	return cleared, nil
+}
+
+// Guild settings and configuration
+
+func (s *MemoryStore) GetGuildSettings(guildID string) (*models.GuildSettings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	settings, ok := s.settings[guildID]
+	if !ok {
+		settings = models.GuildSettings{
+			GuildID:    guildID,
+			Timezone:   models.DefaultTimezone,
+			DigestHour: models.DefaultDigestHour,
+		}
+	}
+	return &settings, nil
+}
+
+func (s *MemoryStore) SaveGuildSettings(settings *models.GuildSettings) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settings[settings.GuildID] = *settings
+	return nil
+}
+
+func (s *MemoryStore) ImportGuildConfig(users []models.MonitoredUser, settings *models.GuildSettings, grants []models.PermissionGrant) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, user := range users {
+		s.saveUser(user)
+	}
+	if settings != nil {
+		s.settings[settings.GuildID] = *settings
+	}
+	for idx := range grants {
+		grant := grants[idx]
+		s.savePermissionGrant(&grant)
+	}
+	return nil
+}
+
+// Creator aliases
+
+func (s *MemoryStore) AddCreatorAlias(alias *models.CreatorAlias) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for idx := range s.aliases {
+		if s.aliases[idx].UserID == alias.UserID && s.aliases[idx].Username == alias.Username {
+			s.aliases[idx].ReplacedAt = alias.ReplacedAt
+			return nil
+		}
+	}
+	s.aliases = append(s.aliases, *alias)
+	return nil
+}
+
+func (s *MemoryStore) GetCreatorAlias(username string) (*models.CreatorAlias, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var found *models.CreatorAlias
+	for idx := range s.aliases {
+		alias := s.aliases[idx]
+		if strings.EqualFold(alias.Username, username) && (found == nil || alias.ReplacedAt > found.ReplacedAt) {
+			found = &alias
+		}
+	}
+	return found, nil
+}
+
+func (s *MemoryStore) GetCreatorAliases(userID string) ([]models.CreatorAlias, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var aliases []models.CreatorAlias
+	for _, alias := range s.aliases {
+		if alias.UserID == userID {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.SliceStable(aliases, func(a, b int) bool { return aliases[a].ReplacedAt > aliases[b].ReplacedAt })
+	return aliases, nil
+}
+
+// Orphaned guilds
+
+func (s *MemoryStore) MarkGuildOrphaned(guildID string, at int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.orphans[guildID]; !ok {
+		s.orphans[guildID] = models.OrphanedGuild{GuildID: guildID, OrphanedAt: at}
+	}
+	return nil
+}
+
+func (s *MemoryStore) GetOrphanedGuild(guildID string) (*models.OrphanedGuild, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if orphan, ok := s.orphans[guildID]; ok {
+		return &orphan, nil
+	}
+	return nil, nil
+}
+
+func (s *MemoryStore) GetOrphanedGuildsBefore(cutoff int64) ([]models.OrphanedGuild, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var orphans []models.OrphanedGuild
+	for _, orphan := range s.orphans {
+		if orphan.OrphanedAt < cutoff {
+			orphans = append(orphans, orphan)
+		}
+	}
+	sort.Slice(orphans, func(a, b int) bool { return orphans[a].GuildID < orphans[b].GuildID })
+	return orphans, nil
+}
+
+func (s *MemoryStore) RestoreOrphanedGuild(guildID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.orphans, guildID)
+	return nil
+}
+
+func (s *MemoryStore) PurgeGuild(guildID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removeWhere(&s.users, func(u models.MonitoredUser) bool { return u.GuildID == guildID })
+	removeWhere(&s.subscriptions, func(sub models.UserSubscription) bool { return sub.GuildID == guildID })
+	removeWhere(&s.grants, func(g models.PermissionGrant) bool { return g.GuildID == guildID })
+	removeWhere(&s.pendingPosts, func(p models.PendingPost) bool { return p.GuildID == guildID })
+	removeWhere(&s.queued, func(n models.QueuedNotification) bool { return n.GuildID == guildID })
+	removeWhere(&s.liveEvents, func(e models.LiveEvent) bool { return e.GuildID == guildID })
+	removeWhere(&s.endpoints, func(e models.WebhookEndpoint) bool { return e.GuildID == guildID })
+	for channelID, webhook := range s.channelWebhooks {
+		if webhook.GuildID == guildID {
+			delete(s.channelWebhooks, channelID)
+		}
+	}
+	delete(s.settings, guildID)
+	delete(s.orphans, guildID)
+	return nil
+}
+
+// Digests and quiet hours
+
+func (s *MemoryStore) AddPendingPost(post *models.PendingPost) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	post.ID = s.nextID("pending_posts")
+	s.pendingPosts = append(s.pendingPosts, *post)
+	return nil
+}
+
+func (s *MemoryStore) GetPendingPosts() ([]models.PendingPost, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	posts := filter(s.pendingPosts, func(p models.PendingPost) bool {
+		_, orphaned := s.orphans[p.GuildID]
+		return !orphaned
+	})
+	sort.Slice(posts, func(a, b int) bool {
+		pa, pb := posts[a], posts[b]
+		if pa.GuildID != pb.GuildID {
+			return pa.GuildID < pb.GuildID
+		}
+		if pa.UserID != pb.UserID {
+			return pa.UserID < pb.UserID
+		}
+		if pa.PostedAt != pb.PostedAt {
+			return pa.PostedAt < pb.PostedAt
+		}
+		return pa.ID < pb.ID
+	})
+	return posts, nil
+}
+
+func (s *MemoryStore) DeletePendingPosts(ids []uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removeWhere(&s.pendingPosts, func(p models.PendingPost) bool { return slices.Contains(ids, p.ID) })
+	return nil
+}
+
+func (s *MemoryStore) DeletePendingPostsForUser(guildID, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removeWhere(&s.pendingPosts, func(p models.PendingPost) bool { return p.GuildID == guildID && p.UserID == userID })
+	return nil
+}
+
+func (s *MemoryStore) AddQueuedNotification(notification *models.QueuedNotification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	notification.ID = s.nextID("queued_notifications")
+	notification.CreatedAt = createdAt(notification.CreatedAt)
+	s.queued = append(s.queued, *notification)
+	return nil
+}
+
+func (s *MemoryStore) GetDueQueuedNotifications(now int64) ([]models.QueuedNotification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	due := filter(s.queued, func(n models.QueuedNotification) bool {
+		_, orphaned := s.orphans[n.GuildID]
+		return n.ReleaseAt <= now && !orphaned
+	})
+	sort.Slice(due, func(a, b int) bool {
+		if due[a].CreatedAt != due[b].CreatedAt {
+			return due[a].CreatedAt < due[b].CreatedAt
+		}
+		return due[a].ID < due[b].ID
+	})
+	return due, nil
+}
+
+func (s *MemoryStore) DeleteQueuedNotification(id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removeWhere(&s.queued, func(n models.QueuedNotification) bool { return n.ID == id })
+	return nil
+}
+
+// Permission grants
+
+func (s *MemoryStore) savePermissionGrant(grant *models.PermissionGrant) {
+	removeWhere(&s.grants, func(g models.PermissionGrant) bool {
+		return g.GuildID == grant.GuildID && g.TargetID == grant.TargetID
+	})
+	grant.ID = s.nextID("permission_grants")
+	grant.CreatedAt = createdAt(grant.CreatedAt)
+	s.grants = append(s.grants, *grant)
+}
+
+func (s *MemoryStore) SavePermissionGrant(grant *models.PermissionGrant) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.savePermissionGrant(grant)
+	return nil
+}
+
+func (s *MemoryStore) GetPermissionGrants(guildID string) ([]models.PermissionGrant, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return filter(s.grants, func(g models.PermissionGrant) bool { return g.GuildID == guildID }), nil
+}
+
+func (s *MemoryStore) DeletePermissionGrant(guildID, targetID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if removeWhere(&s.grants, func(g models.PermissionGrant) bool { return g.GuildID == guildID && g.TargetID == targetID }) == 0 {
+		return ErrGrantNotFound
+	}
+	return nil
+}
+
+func (s *MemoryStore) DeletePermissionGrantsInGuild(guildID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removeWhere(&s.grants, func(g models.PermissionGrant) bool { return g.GuildID == guildID })
+	return nil
+}
+
+// Member DM subscriptions
+
+func (s *MemoryStore) AddUserSubscription(sub *models.UserSubscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if slices.ContainsFunc(s.subscriptions, func(existing models.UserSubscription) bool {
+		return existing.DiscordUserID == sub.DiscordUserID && existing.GuildID == sub.GuildID && existing.CreatorID == sub.CreatorID
+	}) {
+		return fmt.Errorf("member %s is already subscribed to creator %s in guild %s", sub.DiscordUserID, sub.CreatorID, sub.GuildID)
+	}
+	sub.ID = s.nextID("user_subscriptions")
+	sub.CreatedAt = createdAt(sub.CreatedAt)
+	s.subscriptions = append(s.subscriptions, *sub)
+	return nil
+}
+
+func (s *MemoryStore) GetUserSubscription(discordUserID, guildID, creatorID string) (*models.UserSubscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sub := range s.subscriptions {
+		if sub.DiscordUserID == discordUserID && sub.GuildID == guildID && sub.CreatorID == creatorID {
+			return &sub, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *MemoryStore) GetUserSubscriptions(discordUserID string) ([]models.UserSubscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return filter(s.subscriptions, func(sub models.UserSubscription) bool { return sub.DiscordUserID == discordUserID }), nil
+}
+
+func (s *MemoryStore) CountUserSubscriptions(discordUserID string) (int64, error) {
+	subs, _ := s.GetUserSubscriptions(discordUserID)
+	return int64(len(subs)), nil
+}
+
+func (s *MemoryStore) GetSubscribersForCreator(creatorID string) ([]models.UserSubscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return filter(s.subscriptions, func(sub models.UserSubscription) bool { return sub.CreatorID == creatorID }), nil
+}
+
+func (s *MemoryStore) DeleteUserSubscription(discordUserID, guildID, creatorID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if removeWhere(&s.subscriptions, func(sub models.UserSubscription) bool {
+		return sub.DiscordUserID == discordUserID && sub.GuildID == guildID && sub.CreatorID == creatorID
+	}) == 0 {
+		return ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+func (s *MemoryStore) DeleteUserSubscriptionsForDiscordUser(discordUserID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removeWhere(&s.subscriptions, func(sub models.UserSubscription) bool { return sub.DiscordUserID == discordUserID })
+	return nil
+}
+
+func (s *MemoryStore) DeleteUserSubscriptionsForCreator(guildID, creatorID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removeWhere(&s.subscriptions, func(sub models.UserSubscription) bool { return sub.GuildID == guildID && sub.CreatorID == creatorID })
+	return nil
+}
+
+func (s *MemoryStore) DeleteUserSubscriptionsInGuild(guildID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removeWhere(&s.subscriptions, func(sub models.UserSubscription) bool { return sub.GuildID == guildID })
+	return nil
+}
+
+func (s *MemoryStore) UpdateUserSubscriptionFailures(discordUserID string, failures int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for idx := range s.subscriptions {
+		if s.subscriptions[idx].DiscordUserID == discordUserID {
+			s.subscriptions[idx].Failures = failures
+		}
+	}
+	return nil
+}
+
+// Discord webhooks, scheduled events and creator health
+
+func (s *MemoryStore) GetChannelWebhook(channelID string) (*models.ChannelWebhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if webhook, ok := s.channelWebhooks[channelID]; ok {
+		return &webhook, nil
+	}
+	return nil, nil
+}
+
+func (s *MemoryStore) SaveChannelWebhook(webhook *models.ChannelWebhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.channelWebhooks[webhook.ChannelID] = *webhook
+	return nil
+}
+
+func (s *MemoryStore) DeleteChannelWebhook(channelID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.channelWebhooks, channelID)
+	return nil
+}
+
+func (s *MemoryStore) SaveLiveEvent(event *models.LiveEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	idx := slices.IndexFunc(s.liveEvents, func(e models.LiveEvent) bool {
+		return e.GuildID == event.GuildID && e.UserID == event.UserID
+	})
+	if idx >= 0 {
+		s.liveEvents[idx] = *event
+		return nil
+	}
+	event.CreatedAt = createdAt(event.CreatedAt)
+	s.liveEvents = append(s.liveEvents, *event)
+	return nil
+}
+
+func (s *MemoryStore) GetLiveEvent(guildID, userID string) (*models.LiveEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, event := range s.liveEvents {
+		if event.GuildID == guildID && event.UserID == userID {
+			return &event, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *MemoryStore) GetLiveEvents() ([]models.LiveEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.liveEvents), nil
+}
+
+func (s *MemoryStore) DeleteLiveEvent(guildID, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removeWhere(&s.liveEvents, func(e models.LiveEvent) bool { return e.GuildID == guildID && e.UserID == userID })
+	return nil
+}
+
+func (s *MemoryStore) GetAllCreatorHealth() ([]models.CreatorHealth, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	health := make([]models.CreatorHealth, 0, len(s.health))
+	for _, h := range s.health {
+		health = append(health, h)
+	}
+	sort.Slice(health, func(a, b int) bool { return health[a].CreatorID < health[b].CreatorID })
+	return health, nil
+}
+
+func (s *MemoryStore) SaveCreatorHealth(health []models.CreatorHealth) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, h := range health {
+		s.health[h.CreatorID] = h
+	}
+	return nil
+}
+
+func (s *MemoryStore) DeleteCreatorHealth(creatorID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.health, creatorID)
+	return nil
+}
+
+func (s *MemoryStore) MarkCreatorUnmonitored(creatorID string, at int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.unmonitored[creatorID]; !ok {
+		s.unmonitored[creatorID] = models.UnmonitoredCreator{CreatorID: creatorID, Since: at}
+	}
+	return nil
+}
+
+func (s *MemoryStore) GetUnmonitoredCreators() ([]models.UnmonitoredCreator, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	creators := make([]models.UnmonitoredCreator, 0, len(s.unmonitored))
+	for _, creator := range s.unmonitored {
+		creators = append(creators, creator)
+	}
+	sort.Slice(creators, func(a, b int) bool {
+		if creators[a].Since != creators[b].Since {
+			return creators[a].Since < creators[b].Since
+		}
+		return creators[a].CreatorID < creators[b].CreatorID
+	})
+	return creators, nil
+}
+
+func (s *MemoryStore) DeleteUnmonitoredCreator(creatorID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.unmonitored, creatorID)
+	return nil
+}
+
+// Outbound webhook endpoints
+
+func (s *MemoryStore) AddWebhookEndpoint(endpoint *models.WebhookEndpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	endpoint.ID = s.nextID("webhook_endpoints")
+	endpoint.CreatedAt = createdAt(endpoint.CreatedAt)
+	s.endpoints = append(s.endpoints, *endpoint)
+	return nil
+}
+
+func (s *MemoryStore) GetWebhookEndpointsForGuild(guildID string) ([]models.WebhookEndpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return filter(s.endpoints, func(e models.WebhookEndpoint) bool { return e.GuildID == guildID }), nil
+}
+
+func (s *MemoryStore) GetWebhookEndpoint(guildID string, id uint) (*models.WebhookEndpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, endpoint := range s.endpoints {
+		if endpoint.GuildID == guildID && endpoint.ID == id {
+			return &endpoint, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *MemoryStore) CountWebhookEndpointsForGuild(guildID string) (int64, error) {
+	endpoints, _ := s.GetWebhookEndpointsForGuild(guildID)
+	return int64(len(endpoints)), nil
+}
+
+func (s *MemoryStore) DeleteWebhookEndpoint(guildID string, id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if removeWhere(&s.endpoints, func(e models.WebhookEndpoint) bool { return e.GuildID == guildID && e.ID == id }) == 0 {
+		return ErrEndpointNotFound
+	}
+	removeWhere(&s.deadLetters, func(d models.WebhookDeadLetter) bool { return d.EndpointID == id })
+	return nil
+}
+
+func (s *MemoryStore) AddWebhookDeadLetter(deadLetter *models.WebhookDeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deadLetter.ID = s.nextID("webhook_dead_letters")
+	deadLetter.CreatedAt = createdAt(deadLetter.CreatedAt)
+	s.deadLetters = append(s.deadLetters, *deadLetter)
+	return nil
+}
+
+func (s *MemoryStore) CountWebhookDeadLetters(endpointID uint) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return int64(len(filter(s.deadLetters, func(d models.WebhookDeadLetter) bool { return d.EndpointID == endpointID }))), nil
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+	"gorm.io/gorm"
+)
+
+// MarkGuildOrphaned records that the bot left a guild, keeping the earliest time if already marked
+func (r *Repository) MarkGuildOrphaned(guildID string, at int64) error {
+	return WithRetry(func() error {
+		return r.db.Where(models.OrphanedGuild{GuildID: guildID}).
+			Attrs(models.OrphanedGuild{OrphanedAt: at}).
+			FirstOrCreate(&models.OrphanedGuild{}).Error
+	})
+}
+
+// GetOrphanedGuild returns the orphan mark of a guild, or nil if it has none
+func (r *Repository) GetOrphanedGuild(guildID string) (*models.OrphanedGuild, error) {
+	var orphan models.OrphanedGuild
+	err := WithRetry(func() error {
+		return r.db.Where("guild_id = ?", guildID).First(&orphan).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &orphan, nil
+}
+
+// GetOrphanedGuildsBefore returns guilds orphaned before the cutoff
+func (r *Repository) GetOrphanedGuildsBefore(cutoff int64) ([]models.OrphanedGuild, error) {
+	var orphans []models.OrphanedGuild
+	err := WithRetry(func() error {
+		return r.db.Where("orphaned_at < ?", cutoff).Find(&orphans).Error
+	})
+	return orphans, err
+}
+
+// RestoreOrphanedGuild removes the orphan mark so the guild is monitored again
+func (r *Repository) RestoreOrphanedGuild(guildID string) error {
+	return WithRetry(func() error {
+		return r.db.Delete(&models.OrphanedGuild{}, "guild_id = ?", guildID).Error
+	})
+}
+
+// PurgeGuild deletes everything stored for a guild
+func (r *Repository) PurgeGuild(guildID string) error {
+	return WithRetry(func() error {
+		return r.db.Transaction(func(tx *gorm.DB) error {
+			for _, model := range []any{
+				&models.MonitoredUser{},
+				&models.UserSubscription{},
+				&models.PermissionGrant{},
+				&models.GuildSettings{},
+				&models.PendingPost{},
+				&models.QueuedNotification{},
+				&models.ChannelWebhook{},
+				&models.LiveEvent{},
+				&models.WebhookEndpoint{},
+				&models.OrphanedGuild{},
+			} {
+				if err := tx.Delete(model, "guild_id = ?", guildID).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
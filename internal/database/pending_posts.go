@@ -0,0 +1,39 @@
+package database
+
+import (
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+// AddPendingPost queues a post for the next digest of a subscription
+func (r *Repository) AddPendingPost(post *models.PendingPost) error {
+	return WithRetry(func() error {
+		return r.db.Create(post).Error
+	})
+}
+
+// GetPendingPosts returns all queued digest posts outside orphaned guilds, oldest first
+func (r *Repository) GetPendingPosts() ([]models.PendingPost, error) {
+	var posts []models.PendingPost
+	err := WithRetry(func() error {
+		orphaned := r.db.Model(&models.OrphanedGuild{}).Select("guild_id")
+		return r.db.Where("guild_id NOT IN (?)", orphaned).Order("guild_id, user_id, posted_at, id").Find(&posts).Error
+	})
+	return posts, err
+}
+
+// DeletePendingPosts removes queued posts once they have been delivered
+func (r *Repository) DeletePendingPosts(ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return WithRetry(func() error {
+		return r.db.Delete(&models.PendingPost{}, ids).Error
+	})
+}
+
+// DeletePendingPostsForUser removes all queued posts for a subscription
+func (r *Repository) DeletePendingPostsForUser(guildID, userID string) error {
+	return WithRetry(func() error {
+		return r.db.Delete(&models.PendingPost{}, "guild_id = ? AND user_id = ?", guildID, userID).Error
+	})
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+	"gorm.io/gorm"
+)
+
+// SavePermissionGrant stores a grant, replacing any existing scope for the same target
+func (r *Repository) SavePermissionGrant(grant *models.PermissionGrant) error {
+	return WithRetry(func() error {
+		return r.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Delete(&models.PermissionGrant{}, "guild_id = ? AND target_id = ?", grant.GuildID, grant.TargetID).Error; err != nil {
+				return err
+			}
+			return tx.Create(grant).Error
+		})
+	})
+}
+
+func (r *Repository) GetPermissionGrants(guildID string) ([]models.PermissionGrant, error) {
+	var grants []models.PermissionGrant
+	err := WithRetry(func() error {
+		return r.db.Where("guild_id = ?", guildID).Order("id").Find(&grants).Error
+	})
+	return grants, err
+}
+
+func (r *Repository) DeletePermissionGrant(guildID, targetID string) error {
+	return WithRetry(func() error {
+		result := r.db.Delete(&models.PermissionGrant{}, "guild_id = ? AND target_id = ?", guildID, targetID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrGrantNotFound
+		}
+		return nil
+	})
+}
+
+func (r *Repository) DeletePermissionGrantsInGuild(guildID string) error {
+	return WithRetry(func() error {
+		return r.db.Delete(&models.PermissionGrant{}, "guild_id = ?", guildID).Error
+	})
+}
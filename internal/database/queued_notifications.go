@@ -0,0 +1,30 @@
+package database
+
+import (
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+// AddQueuedNotification holds a notification back until its release time
+func (r *Repository) AddQueuedNotification(notification *models.QueuedNotification) error {
+	return WithRetry(func() error {
+		return r.db.Create(notification).Error
+	})
+}
+
+// GetDueQueuedNotifications returns queued notifications outside orphaned
+// guilds whose release time has passed, oldest first
+func (r *Repository) GetDueQueuedNotifications(now int64) ([]models.QueuedNotification, error) {
+	var notifications []models.QueuedNotification
+	err := WithRetry(func() error {
+		orphaned := r.db.Model(&models.OrphanedGuild{}).Select("guild_id")
+		return r.db.Where("release_at <= ? AND guild_id NOT IN (?)", now, orphaned).Order("created_at, id").Find(&notifications).Error
+	})
+	return notifications, err
+}
+
+// DeleteQueuedNotification removes a queued notification once it has been handled
+func (r *Repository) DeleteQueuedNotification(id uint) error {
+	return WithRetry(func() error {
+		return r.db.Delete(&models.QueuedNotification{}, id).Error
+	})
+}
@@ -19,11 +19,12 @@ func NewRepository() *Repository {
 	return &Repository{db: DB}
 }
 
-// GetMonitoredUsers returns all monitored users
+// GetMonitoredUsers returns all monitored users, except those of guilds the bot was removed from
 func (r *Repository) GetMonitoredUsers() ([]models.MonitoredUser, error) {
 	var users []models.MonitoredUser
 	err := WithRetry(func() error {
-		return r.db.Find(&users).Error
+		orphaned := r.db.Model(&models.OrphanedGuild{}).Select("guild_id")
+		return r.db.Where("guild_id NOT IN (?)", orphaned).Find(&users).Error
 	})
 	return users, err
 }
@@ -77,7 +78,9 @@ func (r *Repository) AddOrUpdateMonitoredUser(user *models.MonitoredUser) error
 				"username", "notification_channel", "post_notification_channel", "live_notification_channel",
 				"last_post_id", "last_stream_start", "mention_role", "avatar_location",
 				"avatar_location_updated_at", "live_image_url", "posts_enabled", "live_enabled",
-				"live_mention_role", "post_mention_role",
+				"live_mention_role", "post_mention_role", "post_delivery_mode", "muted_until",
+				"webhook_delivery", "thread_live", "thread_posts", "auto_crosspost", "notify_buttons",
+				"last_delivery_error", "last_delivery_error_at", "posts_suspended",
 			}),
 		}).Create(user).Error
 	})
@@ -104,7 +107,7 @@ func (r *Repository) DeleteMonitoredUserByUsername(guildID, username string) err
 			return result.Error
 		}
 		if result.RowsAffected == 0 {
-			return errors.New("user not found")
+			return ErrUserNotFound
 		}
 		return nil
 	})
@@ -146,14 +149,33 @@ func (r *Repository) UpdateLastStreamStart(guildID, userID string, timestamp int
 	})
 }
 
-// UpdateAvatarInfo updates the avatar information for a monitored user
-func (r *Repository) UpdateAvatarInfo(guildID, userID, avatarLocation string) error {
+// UpdateIsLive records whether a monitored user's stream is currently live
+func (r *Repository) UpdateIsLive(guildID, userID string, isLive bool) error {
+	return WithRetry(func() error {
+		return r.db.Model(&models.MonitoredUser{}).
+			Where("guild_id = ? AND user_id = ?", guildID, userID).
+			Update("is_live", isLive).Error
+	})
+}
+
+// UpdateUsername renames a creator in every guild that monitors them
+func (r *Repository) UpdateUsername(userID, username string) error {
+	return WithRetry(func() error {
+		return r.db.Model(&models.MonitoredUser{}).
+			Where("user_id = ?", userID).
+			Update("username", username).Error
+	})
+}
+
+// UpdateAvatarInfo updates the avatar information for a monitored user,
+// recording when it was refreshed
+func (r *Repository) UpdateAvatarInfo(guildID, userID, avatarLocation string, updatedAt int64) error {
 	return WithRetry(func() error {
 		return r.db.Model(&models.MonitoredUser{}).
 			Where("guild_id = ? AND user_id = ?", guildID, userID).
 			Updates(map[string]any{
 				"avatar_location":            avatarLocation,
-				"avatar_location_updated_at": time.Now().Unix(),
+				"avatar_location_updated_at": updatedAt,
 			}).Error
 	})
 }
@@ -167,7 +189,7 @@ func (r *Repository) UpdateLastPostIDByUsername(guildID, username, postID string
 			return result.Error
 		}
 		if result.RowsAffected == 0 {
-			return errors.New("user not found")
+			return ErrUserNotFound
 		}
 		return nil
 	})
@@ -185,7 +207,7 @@ func (r *Repository) UpdateAvatarInfoByUsername(guildID, username, avatarLocatio
 			return result.Error
 		}
 		if result.RowsAffected == 0 {
-			return errors.New("user not found")
+			return ErrUserNotFound
 		}
 		return nil
 	})
@@ -195,12 +217,12 @@ func (r *Repository) DisablePostsByUsername(guildID, username string) error {
 	return WithRetry(func() error {
 		result := r.db.Model(&models.MonitoredUser{}).
 			Where("guild_id = ? AND username = ?", guildID, username).
-			Update("posts_enabled", false)
+			Updates(map[string]any{"posts_enabled": false, "posts_suspended": false})
 		if result.Error != nil {
 			return result.Error
 		}
 		if result.RowsAffected == 0 {
-			return errors.New("user not found")
+			return ErrUserNotFound
 		}
 		return nil
 	})
@@ -210,12 +232,12 @@ func (r *Repository) EnablePostsByUsername(guildID, username string) error {
 	return WithRetry(func() error {
 		result := r.db.Model(&models.MonitoredUser{}).
 			Where("guild_id = ? AND username = ?", guildID, username).
-			Update("posts_enabled", true)
+			Updates(map[string]any{"posts_enabled": true, "posts_suspended": false})
 		if result.Error != nil {
 			return result.Error
 		}
 		if result.RowsAffected == 0 {
-			return errors.New("user not found")
+			return ErrUserNotFound
 		}
 		return nil
 	})
@@ -230,7 +252,7 @@ func (r *Repository) DisableLiveByUsername(guildID, username string) error {
 			return result.Error
 		}
 		if result.RowsAffected == 0 {
-			return errors.New("user not found")
+			return ErrUserNotFound
 		}
 		return nil
 	})
@@ -245,7 +267,7 @@ func (r *Repository) EnableLiveByUsername(guildID, username string) error {
 			return result.Error
 		}
 		if result.RowsAffected == 0 {
-			return errors.New("user not found")
+			return ErrUserNotFound
 		}
 		return nil
 	})
@@ -282,7 +304,7 @@ func (r *Repository) UpdateLiveImageURL(guildID, username, imageURL string) erro
 			return result.Error
 		}
 		if result.RowsAffected == 0 {
-			return errors.New("user not found")
+			return ErrUserNotFound
 		}
 		return nil
 	})
@@ -297,7 +319,7 @@ func (r *Repository) UpdatePostChannel(guildID, username, channelID string) erro
 			return result.Error
 		}
 		if result.RowsAffected == 0 {
-			return errors.New("user not found")
+			return ErrUserNotFound
 		}
 		return nil
 	})
@@ -312,7 +334,7 @@ func (r *Repository) UpdateLiveChannel(guildID, username, channelID string) erro
 			return result.Error
 		}
 		if result.RowsAffected == 0 {
-			return errors.New("user not found")
+			return ErrUserNotFound
 		}
 		return nil
 	})
@@ -327,7 +349,7 @@ func (r *Repository) UpdatePostMentionRole(guildID, username, roleID string) err
 			return result.Error
 		}
 		if result.RowsAffected == 0 {
-			return errors.New("user not found")
+			return ErrUserNotFound
 		}
 		return nil
 	})
@@ -342,8 +364,158 @@ func (r *Repository) UpdateLiveMentionRole(guildID, username, roleID string) err
 			return result.Error
 		}
 		if result.RowsAffected == 0 {
-			return errors.New("user not found")
+			return ErrUserNotFound
+		}
+		return nil
+	})
+}
+
+func (r *Repository) UpdateDeliveryModeByUsername(guildID, username, mode string) error {
+	return WithRetry(func() error {
+		result := r.db.Model(&models.MonitoredUser{}).
+			Where("guild_id = ? AND username = ?", guildID, username).
+			Update("post_delivery_mode", mode)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+		return nil
+	})
+}
+
+func (r *Repository) UpdateMutedUntilByUsername(guildID, username string, mutedUntil int64) error {
+	return WithRetry(func() error {
+		result := r.db.Model(&models.MonitoredUser{}).
+			Where("guild_id = ? AND username = ?", guildID, username).
+			Update("muted_until", mutedUntil)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+		return nil
+	})
+}
+
+// ClearExpiredMutes resets mutes that ended at or before now and returns how many were cleared
+func (r *Repository) ClearExpiredMutes(now int64) (int64, error) {
+	var cleared int64
+	err := WithRetry(func() error {
+		result := r.db.Model(&models.MonitoredUser{}).
+			Where("muted_until > 0 AND muted_until <= ?", now).
+			Update("muted_until", 0)
+		cleared = result.RowsAffected
+		return result.Error
+	})
+	return cleared, err
+}
+
+func (r *Repository) UpdateWebhookDeliveryByUsername(guildID, username string, enabled bool) error {
+	return WithRetry(func() error {
+		result := r.db.Model(&models.MonitoredUser{}).
+			Where("guild_id = ? AND username = ?", guildID, username).
+			Update("webhook_delivery", enabled)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+		return nil
+	})
+}
+
+func (r *Repository) UpdatePostThreadsByUsername(guildID, username string, enabled bool) error {
+	return WithRetry(func() error {
+		result := r.db.Model(&models.MonitoredUser{}).
+			Where("guild_id = ? AND username = ?", guildID, username).
+			Update("thread_posts", enabled)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUserNotFound
 		}
 		return nil
 	})
 }
+
+func (r *Repository) UpdateLiveThreadsByUsername(guildID, username string, enabled bool) error {
+	return WithRetry(func() error {
+		result := r.db.Model(&models.MonitoredUser{}).
+			Where("guild_id = ? AND username = ?", guildID, username).
+			Update("thread_live", enabled)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+		return nil
+	})
+}
+
+func (r *Repository) UpdateAutoCrosspostByUsername(guildID, username string, enabled bool) error {
+	return WithRetry(func() error {
+		result := r.db.Model(&models.MonitoredUser{}).
+			Where("guild_id = ? AND username = ?", guildID, username).
+			Update("auto_crosspost", enabled)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+		return nil
+	})
+}
+
+func (r *Repository) UpdateNotifyButtonsByUsername(guildID, username string, enabled bool) error {
+	return WithRetry(func() error {
+		result := r.db.Model(&models.MonitoredUser{}).
+			Where("guild_id = ? AND username = ?", guildID, username).
+			Update("notify_buttons", enabled)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+		return nil
+	})
+}
+
+func (r *Repository) UpdateNotifyRoleID(guildID, userID, roleID string) error {
+	return WithRetry(func() error {
+		return r.db.Model(&models.MonitoredUser{}).
+			Where("guild_id = ? AND user_id = ?", guildID, userID).
+			Update("notify_role_id", roleID).Error
+	})
+}
+
+// UpdatePostsSuspended pauses post notifications while the bot can't read the
+// creator's timeline, or lifts the pause once it can. posts_enabled is left
+// alone, so it always reflects the guild's own choice
+func (r *Repository) UpdatePostsSuspended(guildID, userID string, suspended bool) error {
+	return WithRetry(func() error {
+		return r.db.Model(&models.MonitoredUser{}).
+			Where("guild_id = ? AND user_id = ?", guildID, userID).
+			Update("posts_suspended", suspended).Error
+	})
+}
+
+// UpdateDeliveryError records the last failed delivery for a subscription. An
+// empty message clears it.
+func (r *Repository) UpdateDeliveryError(guildID, userID, message string, at int64) error {
+	return WithRetry(func() error {
+		return r.db.Model(&models.MonitoredUser{}).
+			Where("guild_id = ? AND user_id = ?", guildID, userID).
+			Updates(map[string]any{
+				"last_delivery_error":    message,
+				"last_delivery_error_at": at,
+			}).Error
+	})
+}
@@ -0,0 +1,138 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+// Errors returned when an operation targets a row that does not exist.
+var (
+	ErrUserNotFound         = errors.New("user not found")
+	ErrGrantNotFound        = errors.New("grant not found")
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrEndpointNotFound     = errors.New("endpoint not found")
+)
+
+// Store is everything the bot reads and writes. Repository implements it on
+// top of GORM and MemoryStore keeps the same data in memory for tests.
+//
+// Get methods for a single row return nil without an error when the row does
+// not exist. Updates and deletes addressed by username return ErrUserNotFound
+// when no subscription matches.
+type Store interface {
+	// Monitored users
+	GetMonitoredUsers() ([]models.MonitoredUser, error)
+	GetMonitoredUser(guildID, userID string) (*models.MonitoredUser, error)
+	GetMonitoredUserByUsername(guildID, username string) (*models.MonitoredUser, error)
+	GetMonitoredUsersForGuild(guildID string) ([]models.MonitoredUser, error)
+	CountMonitoredUsersForGuild(guildID string) (int64, error)
+	CountMonitoredUsers() (int64, error)
+	CountGuilds() (int64, error)
+	AddMonitoredUser(user *models.MonitoredUser) error
+	AddOrUpdateMonitoredUser(user *models.MonitoredUser) error
+	UpdateMonitoredUser(user *models.MonitoredUser) error
+	DeleteMonitoredUser(guildID, userID string) error
+	DeleteMonitoredUserByUsername(guildID, username string) error
+	DeleteAllUsersInGuild(guildID string) error
+	UpdateLastPostID(guildID, userID, postID string) error
+	UpdateLastStreamStart(guildID, userID string, timestamp int64) error
+	UpdateIsLive(guildID, userID string, isLive bool) error
+	UpdateUsername(userID, username string) error
+	UpdateAvatarInfo(guildID, userID, avatarLocation string, updatedAt int64) error
+	UpdateNotifyRoleID(guildID, userID, roleID string) error
+	UpdateDeliveryError(guildID, userID, message string, at int64) error
+	UpdatePostsSuspended(guildID, userID string, suspended bool) error
+	UpdateLastPostIDByUsername(guildID, username, postID string) error
+	UpdateAvatarInfoByUsername(guildID, username, avatarLocation string) error
+	DisablePostsByUsername(guildID, username string) error
+	EnablePostsByUsername(guildID, username string) error
+	DisableLiveByUsername(guildID, username string) error
+	EnableLiveByUsername(guildID, username string) error
+	UpdateLiveImageURL(guildID, username, imageURL string) error
+	UpdatePostChannel(guildID, username, channelID string) error
+	UpdateLiveChannel(guildID, username, channelID string) error
+	UpdatePostMentionRole(guildID, username, roleID string) error
+	UpdateLiveMentionRole(guildID, username, roleID string) error
+	UpdateDeliveryModeByUsername(guildID, username, mode string) error
+	UpdateMutedUntilByUsername(guildID, username string, mutedUntil int64) error
+	UpdateWebhookDeliveryByUsername(guildID, username string, enabled bool) error
+	UpdatePostThreadsByUsername(guildID, username string, enabled bool) error
+	UpdateLiveThreadsByUsername(guildID, username string, enabled bool) error
+	UpdateAutoCrosspostByUsername(guildID, username string, enabled bool) error
+	UpdateNotifyButtonsByUsername(guildID, username string, enabled bool) error
+	ClearExpiredMutes(now int64) (int64, error)
+
+	// Guild settings and configuration
+	GetGuildSettings(guildID string) (*models.GuildSettings, error)
+	SaveGuildSettings(settings *models.GuildSettings) error
+	ImportGuildConfig(users []models.MonitoredUser, settings *models.GuildSettings, grants []models.PermissionGrant) error
+
+	// Creator aliases
+	AddCreatorAlias(alias *models.CreatorAlias) error
+	GetCreatorAlias(username string) (*models.CreatorAlias, error)
+	GetCreatorAliases(userID string) ([]models.CreatorAlias, error)
+
+	// Orphaned guilds
+	MarkGuildOrphaned(guildID string, at int64) error
+	GetOrphanedGuild(guildID string) (*models.OrphanedGuild, error)
+	GetOrphanedGuildsBefore(cutoff int64) ([]models.OrphanedGuild, error)
+	RestoreOrphanedGuild(guildID string) error
+	PurgeGuild(guildID string) error
+
+	// Digests and quiet hours
+	AddPendingPost(post *models.PendingPost) error
+	GetPendingPosts() ([]models.PendingPost, error)
+	DeletePendingPosts(ids []uint) error
+	DeletePendingPostsForUser(guildID, userID string) error
+	AddQueuedNotification(notification *models.QueuedNotification) error
+	GetDueQueuedNotifications(now int64) ([]models.QueuedNotification, error)
+	DeleteQueuedNotification(id uint) error
+
+	// Permission grants
+	SavePermissionGrant(grant *models.PermissionGrant) error
+	GetPermissionGrants(guildID string) ([]models.PermissionGrant, error)
+	DeletePermissionGrant(guildID, targetID string) error
+	DeletePermissionGrantsInGuild(guildID string) error
+
+	// Member DM subscriptions
+	AddUserSubscription(sub *models.UserSubscription) error
+	GetUserSubscription(discordUserID, guildID, creatorID string) (*models.UserSubscription, error)
+	GetUserSubscriptions(discordUserID string) ([]models.UserSubscription, error)
+	CountUserSubscriptions(discordUserID string) (int64, error)
+	GetSubscribersForCreator(creatorID string) ([]models.UserSubscription, error)
+	DeleteUserSubscription(discordUserID, guildID, creatorID string) error
+	DeleteUserSubscriptionsForDiscordUser(discordUserID string) error
+	DeleteUserSubscriptionsForCreator(guildID, creatorID string) error
+	DeleteUserSubscriptionsInGuild(guildID string) error
+	UpdateUserSubscriptionFailures(discordUserID string, failures int) error
+
+	// Discord webhooks, scheduled events and creator health
+	GetChannelWebhook(channelID string) (*models.ChannelWebhook, error)
+	SaveChannelWebhook(webhook *models.ChannelWebhook) error
+	DeleteChannelWebhook(channelID string) error
+	SaveLiveEvent(event *models.LiveEvent) error
+	GetLiveEvent(guildID, userID string) (*models.LiveEvent, error)
+	GetLiveEvents() ([]models.LiveEvent, error)
+	DeleteLiveEvent(guildID, userID string) error
+	GetAllCreatorHealth() ([]models.CreatorHealth, error)
+	SaveCreatorHealth(health []models.CreatorHealth) error
+	DeleteCreatorHealth(creatorID string) error
+	MarkCreatorUnmonitored(creatorID string, at int64) error
+	GetUnmonitoredCreators() ([]models.UnmonitoredCreator, error)
+	DeleteUnmonitoredCreator(creatorID string) error
+
+	// Outbound webhook endpoints
+	AddWebhookEndpoint(endpoint *models.WebhookEndpoint) error
+	GetWebhookEndpointsForGuild(guildID string) ([]models.WebhookEndpoint, error)
+	GetWebhookEndpoint(guildID string, id uint) (*models.WebhookEndpoint, error)
+	CountWebhookEndpointsForGuild(guildID string) (int64, error)
+	DeleteWebhookEndpoint(guildID string, id uint) error
+	AddWebhookDeadLetter(deadLetter *models.WebhookDeadLetter) error
+	CountWebhookDeadLetters(endpointID uint) (int64, error)
+}
+
+var (
+	_ Store = (*Repository)(nil)
+	_ Store = (*MemoryStore)(nil)
+)
@@ -0,0 +1,23 @@
+package database_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/fvckgrimm/discord-fansly-notify/internal/database"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/database/storetest"
+)
+
+func TestMemoryStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) database.Store { return database.NewMemoryStore() })
+}
+
+func TestRepository(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) database.Store {
+		if err := database.Init("sqlite", filepath.Join(t.TempDir(), "test.db")); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(database.Close)
+		return database.NewRepository()
+	})
+}
@@ -0,0 +1,813 @@
+// Package storetest checks that a database.Store behaves like the GORM
+// repository. Each implementation runs the same suite from its own test:
+//
+//	func TestMemoryStore(t *testing.T) {
+//		storetest.Run(t, func(t *testing.T) database.Store {
+//			return database.NewMemoryStore()
+//		})
+//	}
+//
+//	func TestRepository(t *testing.T) {
+//		storetest.Run(t, func(t *testing.T) database.Store {
+//			if err := database.Init("sqlite", filepath.Join(t.TempDir(), "test.db")); err != nil {
+//				t.Fatal(err)
+//			}
+//			t.Cleanup(database.Close)
+//			return database.NewRepository()
+//		})
+//	}
+package storetest
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/fvckgrimm/discord-fansly-notify/internal/database"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+// Run runs every conformance test against stores created by newStore. Each
+// test gets a new, empty store.
+func Run(t *testing.T, newStore func(t *testing.T) database.Store) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, store database.Store)
+	}{
+		{"MonitoredUsers", testMonitoredUsers},
+		{"AddOrUpdateMonitoredUser", testAddOrUpdateMonitoredUser},
+		{"UpdatesByUsername", testUpdatesByUsername},
+		{"UserNotFound", testUserNotFound},
+		{"ClearExpiredMutes", testClearExpiredMutes},
+		{"GuildSettings", testGuildSettings},
+		{"CreatorAliases", testCreatorAliases},
+		{"OrphanedGuilds", testOrphanedGuilds},
+		{"PurgeGuild", testPurgeGuild},
+		{"PendingPosts", testPendingPosts},
+		{"QueuedNotifications", testQueuedNotifications},
+		{"PermissionGrants", testPermissionGrants},
+		{"ImportGuildConfig", testImportGuildConfig},
+		{"UserSubscriptions", testUserSubscriptions},
+		{"ChannelWebhooks", testChannelWebhooks},
+		{"LiveEvents", testLiveEvents},
+		{"CreatorHealth", testCreatorHealth},
+		{"UnmonitoredCreators", testUnmonitoredCreators},
+		{"WebhookEndpoints", testWebhookEndpoints},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newStore(t))
+		})
+	}
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func addUser(t *testing.T, store database.Store, guildID, userID, username string) {
+	t.Helper()
+	must(t, store.AddMonitoredUser(&models.MonitoredUser{
+		GuildID:      guildID,
+		UserID:       userID,
+		Username:     username,
+		PostsEnabled: true,
+		LiveEnabled:  true,
+	}))
+}
+
+func getUser(t *testing.T, store database.Store, guildID, userID string) models.MonitoredUser {
+	t.Helper()
+	user, err := store.GetMonitoredUser(guildID, userID)
+	must(t, err)
+	if user == nil {
+		t.Fatalf("user %s in guild %s not found", userID, guildID)
+	}
+	return *user
+}
+
+func testMonitoredUsers(t *testing.T, store database.Store) {
+	addUser(t, store, "g1", "c1", "alice")
+	addUser(t, store, "g1", "c2", "bob")
+	addUser(t, store, "g2", "c1", "alice")
+
+	if err := store.AddMonitoredUser(&models.MonitoredUser{GuildID: "g1", UserID: "c1"}); err == nil {
+		t.Error("adding a duplicate monitored user succeeded")
+	}
+
+	user, err := store.GetMonitoredUser("g1", "missing")
+	must(t, err)
+	if user != nil {
+		t.Errorf("GetMonitoredUser for a missing user = %+v, want nil", user)
+	}
+	user, err = store.GetMonitoredUserByUsername("g1", "bob")
+	must(t, err)
+	if user == nil || user.UserID != "c2" {
+		t.Errorf("GetMonitoredUserByUsername = %+v, want c2", user)
+	}
+	user, err = store.GetMonitoredUserByUsername("g2", "bob")
+	must(t, err)
+	if user != nil {
+		t.Errorf("GetMonitoredUserByUsername in another guild = %+v, want nil", user)
+	}
+
+	guildUsers, err := store.GetMonitoredUsersForGuild("g1")
+	must(t, err)
+	if len(guildUsers) != 2 {
+		t.Errorf("GetMonitoredUsersForGuild returned %d users, want 2", len(guildUsers))
+	}
+	count, err := store.CountMonitoredUsersForGuild("g2")
+	must(t, err)
+	if count != 1 {
+		t.Errorf("CountMonitoredUsersForGuild = %d, want 1", count)
+	}
+	count, err = store.CountMonitoredUsers()
+	must(t, err)
+	if count != 3 {
+		t.Errorf("CountMonitoredUsers = %d, want 3", count)
+	}
+	count, err = store.CountGuilds()
+	must(t, err)
+	if count != 2 {
+		t.Errorf("CountGuilds = %d, want 2", count)
+	}
+
+	must(t, store.UpdateLastPostID("g1", "c1", "p1"))
+	must(t, store.UpdateLastStreamStart("g1", "c1", 100))
+	must(t, store.UpdateIsLive("g1", "c1", true))
+	must(t, store.UpdateNotifyRoleID("g1", "c1", "r1"))
+	must(t, store.UpdateDeliveryError("g1", "c1", "boom", 200))
+	must(t, store.UpdateAvatarInfo("g1", "c1", "avatar.png", 300))
+	got := getUser(t, store, "g1", "c1")
+	if got.LastPostID != "p1" || got.LastStreamStart != 100 || !got.IsLive || got.NotifyRoleID != "r1" ||
+		got.LastDeliveryError != "boom" || got.LastDeliveryErrorAt != 200 || got.AvatarLocation != "avatar.png" || got.AvatarLocationUpdatedAt != 300 {
+		t.Errorf("updates by ID not applied: %+v", got)
+	}
+	if other := getUser(t, store, "g2", "c1"); other.LastPostID != "" {
+		t.Errorf("update leaked into another guild: %+v", other)
+	}
+
+	// Updates by ID ignore missing users, like an SQL update.
+	must(t, store.UpdateLastPostID("g1", "missing", "p1"))
+
+	must(t, store.UpdateUsername("c1", "alice2"))
+	for _, guildID := range []string{"g1", "g2"} {
+		if got := getUser(t, store, guildID, "c1"); got.Username != "alice2" {
+			t.Errorf("UpdateUsername in %s: username = %q, want alice2", guildID, got.Username)
+		}
+	}
+
+	got = getUser(t, store, "g1", "c2")
+	got.MentionRole = "r2"
+	got.IsLive = true
+	must(t, store.UpdateMonitoredUser(&got))
+	if updated := getUser(t, store, "g1", "c2"); updated.MentionRole != "r2" || !updated.IsLive {
+		t.Errorf("UpdateMonitoredUser not applied: %+v", updated)
+	}
+
+	must(t, store.DeleteMonitoredUser("g1", "c2"))
+	must(t, store.DeleteMonitoredUserByUsername("g2", "alice2"))
+	must(t, store.DeleteAllUsersInGuild("g1"))
+	count, err = store.CountMonitoredUsers()
+	must(t, err)
+	if count != 0 {
+		t.Errorf("CountMonitoredUsers after deleting everything = %d, want 0", count)
+	}
+}
+
+func testAddOrUpdateMonitoredUser(t *testing.T, store database.Store) {
+	must(t, store.AddOrUpdateMonitoredUser(&models.MonitoredUser{
+		GuildID:                 "g1",
+		UserID:                  "c1",
+		Username:                "alice",
+		PostNotificationChannel: "ch1",
+		PostsEnabled:            true,
+	}))
+	must(t, store.UpdateIsLive("g1", "c1", true))
+	must(t, store.UpdateNotifyRoleID("g1", "c1", "r1"))
+	must(t, store.UpdateMutedUntilByUsername("g1", "alice", 500))
+	must(t, store.UpdatePostsSuspended("g1", "c1", true))
+
+	must(t, store.AddOrUpdateMonitoredUser(&models.MonitoredUser{
+		GuildID:                 "g1",
+		UserID:                  "c1",
+		Username:                "alice",
+		PostNotificationChannel: "ch2",
+		LiveEnabled:             true,
+	}))
+
+	got := getUser(t, store, "g1", "c1")
+	if got.PostNotificationChannel != "ch2" || got.PostsEnabled || !got.LiveEnabled ||
+		got.MutedUntil != 0 || got.PostsSuspended {
+		t.Errorf("AddOrUpdateMonitoredUser did not update the subscription: %+v", got)
+	}
+	// The live state and the notify role describe Discord and Fansly rather
+	// than the subscription's settings, so they are kept.
+	if !got.IsLive || got.NotifyRoleID != "r1" {
+		t.Errorf("AddOrUpdateMonitoredUser overwrote state it should keep: %+v", got)
+	}
+}
+
+func testUpdatesByUsername(t *testing.T, store database.Store) {
+	addUser(t, store, "g1", "c1", "alice")
+
+	must(t, store.UpdateLastPostIDByUsername("g1", "alice", "p1"))
+	must(t, store.UpdateAvatarInfoByUsername("g1", "alice", "avatar.png"))
+	must(t, store.DisablePostsByUsername("g1", "alice"))
+	must(t, store.DisableLiveByUsername("g1", "alice"))
+	must(t, store.UpdateLiveImageURL("g1", "alice", "https://example.com/live.png"))
+	must(t, store.UpdatePostChannel("g1", "alice", "posts"))
+	must(t, store.UpdateLiveChannel("g1", "alice", "live"))
+	must(t, store.UpdatePostMentionRole("g1", "alice", "r-post"))
+	must(t, store.UpdateLiveMentionRole("g1", "alice", "r-live"))
+	must(t, store.UpdateDeliveryModeByUsername("g1", "alice", models.DeliveryDaily))
+	must(t, store.UpdateMutedUntilByUsername("g1", "alice", 300))
+	must(t, store.UpdateWebhookDeliveryByUsername("g1", "alice", true))
+	must(t, store.UpdatePostThreadsByUsername("g1", "alice", true))
+	must(t, store.UpdateLiveThreadsByUsername("g1", "alice", true))
+	must(t, store.UpdateAutoCrosspostByUsername("g1", "alice", true))
+	must(t, store.UpdateNotifyButtonsByUsername("g1", "alice", true))
+
+	got := getUser(t, store, "g1", "c1")
+	want := got
+	want.LastPostID = "p1"
+	want.AvatarLocation = "avatar.png"
+	want.PostsEnabled = false
+	want.LiveEnabled = false
+	want.LiveImageURL = "https://example.com/live.png"
+	want.PostNotificationChannel = "posts"
+	want.LiveNotificationChannel = "live"
+	want.PostMentionRole = "r-post"
+	want.LiveMentionRole = "r-live"
+	want.PostDeliveryMode = models.DeliveryDaily
+	want.MutedUntil = 300
+	want.WebhookDelivery = true
+	want.ThreadPosts = true
+	want.ThreadLive = true
+	want.AutoCrosspost = true
+	want.NotifyButtons = true
+	if got != want || got.AvatarLocationUpdatedAt == 0 {
+		t.Errorf("updates by username not applied:\n got %+v\nwant %+v", got, want)
+	}
+
+	must(t, store.EnablePostsByUsername("g1", "alice"))
+	must(t, store.EnableLiveByUsername("g1", "alice"))
+	if got := getUser(t, store, "g1", "c1"); !got.PostsEnabled || !got.LiveEnabled {
+		t.Errorf("enabling notifications not applied: %+v", got)
+	}
+
+	must(t, store.UpdatePostsSuspended("g1", "c1", true))
+	if got := getUser(t, store, "g1", "c1"); !got.PostsEnabled || !got.PostsSuspended {
+		t.Errorf("suspending posts not applied: %+v", got)
+	}
+	must(t, store.UpdatePostsSuspended("g1", "c1", false))
+	if got := getUser(t, store, "g1", "c1"); !got.PostsEnabled || got.PostsSuspended {
+		t.Errorf("resuming posts not applied: %+v", got)
+	}
+	must(t, store.UpdatePostsSuspended("g1", "c1", true))
+	// Turning posts off by hand means they must not come back on their own.
+	must(t, store.DisablePostsByUsername("g1", "alice"))
+	if got := getUser(t, store, "g1", "c1"); got.PostsEnabled || got.PostsSuspended {
+		t.Errorf("disabling suspended posts kept them suspended: %+v", got)
+	}
+}
+
+func testUserNotFound(t *testing.T, store database.Store) {
+	addUser(t, store, "g1", "c1", "alice")
+
+	updates := map[string]func() error{
+		"DeleteMonitoredUserByUsername":   func() error { return store.DeleteMonitoredUserByUsername("g2", "alice") },
+		"UpdateLastPostIDByUsername":      func() error { return store.UpdateLastPostIDByUsername("g1", "bob", "p1") },
+		"UpdateAvatarInfoByUsername":      func() error { return store.UpdateAvatarInfoByUsername("g1", "bob", "a") },
+		"DisablePostsByUsername":          func() error { return store.DisablePostsByUsername("g1", "bob") },
+		"EnablePostsByUsername":           func() error { return store.EnablePostsByUsername("g1", "bob") },
+		"DisableLiveByUsername":           func() error { return store.DisableLiveByUsername("g1", "bob") },
+		"EnableLiveByUsername":            func() error { return store.EnableLiveByUsername("g1", "bob") },
+		"UpdateLiveImageURL":              func() error { return store.UpdateLiveImageURL("g1", "bob", "u") },
+		"UpdatePostChannel":               func() error { return store.UpdatePostChannel("g1", "bob", "ch") },
+		"UpdateLiveChannel":               func() error { return store.UpdateLiveChannel("g1", "bob", "ch") },
+		"UpdatePostMentionRole":           func() error { return store.UpdatePostMentionRole("g1", "bob", "r") },
+		"UpdateLiveMentionRole":           func() error { return store.UpdateLiveMentionRole("g1", "bob", "r") },
+		"UpdateDeliveryModeByUsername":    func() error { return store.UpdateDeliveryModeByUsername("g1", "bob", models.DeliveryHourly) },
+		"UpdateMutedUntilByUsername":      func() error { return store.UpdateMutedUntilByUsername("g1", "bob", 1) },
+		"UpdateWebhookDeliveryByUsername": func() error { return store.UpdateWebhookDeliveryByUsername("g1", "bob", true) },
+		"UpdatePostThreadsByUsername":     func() error { return store.UpdatePostThreadsByUsername("g1", "bob", true) },
+		"UpdateLiveThreadsByUsername":     func() error { return store.UpdateLiveThreadsByUsername("g1", "bob", true) },
+		"UpdateAutoCrosspostByUsername":   func() error { return store.UpdateAutoCrosspostByUsername("g1", "bob", true) },
+		"UpdateNotifyButtonsByUsername":   func() error { return store.UpdateNotifyButtonsByUsername("g1", "bob", true) },
+	}
+	for name, update := range updates {
+		if err := update(); !errors.Is(err, database.ErrUserNotFound) {
+			t.Errorf("%s for a missing user returned %v, want ErrUserNotFound", name, err)
+		}
+	}
+}
+
+func testClearExpiredMutes(t *testing.T, store database.Store) {
+	addUser(t, store, "g1", "c1", "alice")
+	addUser(t, store, "g1", "c2", "bob")
+	addUser(t, store, "g1", "c3", "carol")
+	must(t, store.UpdateMutedUntilByUsername("g1", "alice", 100))
+	must(t, store.UpdateMutedUntilByUsername("g1", "bob", 200))
+
+	cleared, err := store.ClearExpiredMutes(100)
+	must(t, err)
+	if cleared != 1 {
+		t.Errorf("ClearExpiredMutes cleared %d mutes, want 1", cleared)
+	}
+	if got := getUser(t, store, "g1", "c1"); got.MutedUntil != 0 {
+		t.Errorf("expired mute kept: %d", got.MutedUntil)
+	}
+	if got := getUser(t, store, "g1", "c2"); got.MutedUntil != 200 {
+		t.Errorf("active mute cleared: %d", got.MutedUntil)
+	}
+}
+
+func testGuildSettings(t *testing.T, store database.Store) {
+	settings, err := store.GetGuildSettings("g1")
+	must(t, err)
+	if settings.GuildID != "g1" || settings.Timezone != models.DefaultTimezone || settings.DigestHour != models.DefaultDigestHour {
+		t.Errorf("default settings = %+v", settings)
+	}
+
+	settings.Timezone = "Europe/Berlin"
+	settings.QuietHoursEnabled = true
+	must(t, store.SaveGuildSettings(settings))
+
+	got, err := store.GetGuildSettings("g1")
+	must(t, err)
+	if *got != *settings {
+		t.Errorf("GetGuildSettings = %+v, want %+v", got, settings)
+	}
+}
+
+func testCreatorAliases(t *testing.T, store database.Store) {
+	alias, err := store.GetCreatorAlias("alice")
+	must(t, err)
+	if alias != nil {
+		t.Errorf("GetCreatorAlias for an unknown name = %+v, want nil", alias)
+	}
+
+	must(t, store.AddCreatorAlias(&models.CreatorAlias{UserID: "c1", Username: "alice", ReplacedAt: 100}))
+	must(t, store.AddCreatorAlias(&models.CreatorAlias{UserID: "c1", Username: "alice2", ReplacedAt: 200}))
+	// Another creator later took the name.
+	must(t, store.AddCreatorAlias(&models.CreatorAlias{UserID: "c2", Username: "alice", ReplacedAt: 300}))
+
+	alias, err = store.GetCreatorAlias("ALICE")
+	must(t, err)
+	if alias == nil || alias.UserID != "c2" {
+		t.Errorf("GetCreatorAlias(ALICE) = %+v, want the newest alias of c2", alias)
+	}
+
+	// Recording an alias again only moves its time.
+	must(t, store.AddCreatorAlias(&models.CreatorAlias{UserID: "c1", Username: "alice", ReplacedAt: 400}))
+	aliases, err := store.GetCreatorAliases("c1")
+	must(t, err)
+	if len(aliases) != 2 || aliases[0].Username != "alice" || aliases[0].ReplacedAt != 400 || aliases[1].Username != "alice2" {
+		t.Errorf("GetCreatorAliases(c1) = %+v, want alice at 400 then alice2", aliases)
+	}
+}
+
+func testOrphanedGuilds(t *testing.T, store database.Store) {
+	addUser(t, store, "g1", "c1", "alice")
+	addUser(t, store, "g2", "c1", "alice")
+
+	must(t, store.MarkGuildOrphaned("g1", 100))
+	must(t, store.MarkGuildOrphaned("g1", 200))
+
+	orphan, err := store.GetOrphanedGuild("g1")
+	must(t, err)
+	if orphan == nil || orphan.OrphanedAt != 100 {
+		t.Errorf("GetOrphanedGuild = %+v, want the first mark at 100", orphan)
+	}
+	orphan, err = store.GetOrphanedGuild("g2")
+	must(t, err)
+	if orphan != nil {
+		t.Errorf("GetOrphanedGuild for a guild that wasn't orphaned = %+v, want nil", orphan)
+	}
+
+	users, err := store.GetMonitoredUsers()
+	must(t, err)
+	if len(users) != 1 || users[0].GuildID != "g2" {
+		t.Errorf("GetMonitoredUsers = %+v, want only the subscription of g2", users)
+	}
+
+	for _, guildID := range []string{"g1", "g2"} {
+		must(t, store.AddPendingPost(&models.PendingPost{GuildID: guildID, UserID: "c1", PostID: "p1"}))
+		must(t, store.AddQueuedNotification(&models.QueuedNotification{GuildID: guildID, UserID: "c1", ReleaseAt: 1}))
+	}
+	posts, err := store.GetPendingPosts()
+	must(t, err)
+	if len(posts) != 1 || posts[0].GuildID != "g2" {
+		t.Errorf("GetPendingPosts = %+v, want only the post of g2", posts)
+	}
+	queued, err := store.GetDueQueuedNotifications(1)
+	must(t, err)
+	if len(queued) != 1 || queued[0].GuildID != "g2" {
+		t.Errorf("GetDueQueuedNotifications = %+v, want only the notification of g2", queued)
+	}
+
+	orphans, err := store.GetOrphanedGuildsBefore(100)
+	must(t, err)
+	if len(orphans) != 0 {
+		t.Errorf("GetOrphanedGuildsBefore(100) = %+v, want none", orphans)
+	}
+	orphans, err = store.GetOrphanedGuildsBefore(101)
+	must(t, err)
+	if len(orphans) != 1 || orphans[0].GuildID != "g1" {
+		t.Errorf("GetOrphanedGuildsBefore(101) = %+v, want g1", orphans)
+	}
+
+	must(t, store.RestoreOrphanedGuild("g1"))
+	users, err = store.GetMonitoredUsers()
+	must(t, err)
+	if len(users) != 2 {
+		t.Errorf("GetMonitoredUsers after restoring returned %d users, want 2", len(users))
+	}
+	posts, err = store.GetPendingPosts()
+	must(t, err)
+	if len(posts) != 2 {
+		t.Errorf("GetPendingPosts after restoring returned %d posts, want 2", len(posts))
+	}
+}
+
+func testPurgeGuild(t *testing.T, store database.Store) {
+	for _, guildID := range []string{"g1", "g2"} {
+		addUser(t, store, guildID, "c1", "alice")
+		must(t, store.SaveGuildSettings(&models.GuildSettings{GuildID: guildID, Timezone: "UTC", DigestHour: 3}))
+		must(t, store.AddUserSubscription(&models.UserSubscription{DiscordUserID: "m1", GuildID: guildID, CreatorID: "c1"}))
+		must(t, store.SavePermissionGrant(&models.PermissionGrant{GuildID: guildID, TargetType: models.GrantTargetRole, TargetID: "r1", Scope: models.ScopeRead}))
+		must(t, store.AddPendingPost(&models.PendingPost{GuildID: guildID, UserID: "c1", PostID: "p1"}))
+		must(t, store.AddQueuedNotification(&models.QueuedNotification{GuildID: guildID, UserID: "c1", ReleaseAt: 1}))
+		must(t, store.SaveChannelWebhook(&models.ChannelWebhook{ChannelID: "ch-" + guildID, GuildID: guildID}))
+		must(t, store.SaveLiveEvent(&models.LiveEvent{GuildID: guildID, UserID: "c1", EventID: "e1"}))
+		must(t, store.AddWebhookEndpoint(&models.WebhookEndpoint{GuildID: guildID, URL: "https://example.com"}))
+	}
+	must(t, store.MarkGuildOrphaned("g1", 100))
+
+	must(t, store.PurgeGuild("g1"))
+
+	users, err := store.GetMonitoredUsersForGuild("g1")
+	must(t, err)
+	grants, err := store.GetPermissionGrants("g1")
+	must(t, err)
+	endpoints, err := store.GetWebhookEndpointsForGuild("g1")
+	must(t, err)
+	sub, err := store.GetUserSubscription("m1", "g1", "c1")
+	must(t, err)
+	webhook, err := store.GetChannelWebhook("ch-g1")
+	must(t, err)
+	event, err := store.GetLiveEvent("g1", "c1")
+	must(t, err)
+	orphan, err := store.GetOrphanedGuild("g1")
+	must(t, err)
+	settings, err := store.GetGuildSettings("g1")
+	must(t, err)
+	if len(users) != 0 || len(grants) != 0 || len(endpoints) != 0 || sub != nil || webhook != nil || event != nil || orphan != nil {
+		t.Error("PurgeGuild left data of the purged guild behind")
+	}
+	if settings.DigestHour != models.DefaultDigestHour {
+		t.Errorf("PurgeGuild kept the guild settings: %+v", settings)
+	}
+
+	posts, err := store.GetPendingPosts()
+	must(t, err)
+	queued, err := store.GetDueQueuedNotifications(1)
+	must(t, err)
+	if len(posts) != 1 || posts[0].GuildID != "g2" || len(queued) != 1 || queued[0].GuildID != "g2" {
+		t.Errorf("PurgeGuild touched digests or queued notifications of another guild: %+v %+v", posts, queued)
+	}
+	if users, _ := store.GetMonitoredUsersForGuild("g2"); len(users) != 1 {
+		t.Error("PurgeGuild deleted subscriptions of another guild")
+	}
+}
+
+func testPendingPosts(t *testing.T, store database.Store) {
+	posts := []models.PendingPost{
+		{GuildID: "g2", UserID: "c1", PostID: "p1", PostedAt: 10},
+		{GuildID: "g1", UserID: "c2", PostID: "p2", PostedAt: 30},
+		{GuildID: "g1", UserID: "c1", PostID: "p3", PostedAt: 20},
+		{GuildID: "g1", UserID: "c1", PostID: "p4", PostedAt: 10},
+	}
+	for idx := range posts {
+		must(t, store.AddPendingPost(&posts[idx]))
+		if posts[idx].ID == 0 {
+			t.Fatal("AddPendingPost did not assign an ID")
+		}
+	}
+
+	got, err := store.GetPendingPosts()
+	must(t, err)
+	var order []string
+	for _, post := range got {
+		order = append(order, post.PostID)
+	}
+	if want := []string{"p4", "p3", "p2", "p1"}; !slices.Equal(order, want) {
+		t.Errorf("GetPendingPosts order = %v, want %v", order, want)
+	}
+
+	must(t, store.DeletePendingPosts([]uint{posts[0].ID}))
+	must(t, store.DeletePendingPosts(nil))
+	must(t, store.DeletePendingPostsForUser("g1", "c1"))
+	got, err = store.GetPendingPosts()
+	must(t, err)
+	if len(got) != 1 || got[0].PostID != "p2" {
+		t.Errorf("GetPendingPosts after deleting = %+v, want only p2", got)
+	}
+}
+
+func testQueuedNotifications(t *testing.T, store database.Store) {
+	queued := []models.QueuedNotification{
+		{GuildID: "g1", UserID: "c1", Content: "late", ReleaseAt: 200, CreatedAt: 1},
+		{GuildID: "g1", UserID: "c1", Content: "second", ReleaseAt: 100, CreatedAt: 3},
+		{GuildID: "g1", UserID: "c1", Content: "first", ReleaseAt: 50, CreatedAt: 2},
+	}
+	for idx := range queued {
+		must(t, store.AddQueuedNotification(&queued[idx]))
+	}
+
+	due, err := store.GetDueQueuedNotifications(100)
+	must(t, err)
+	if len(due) != 2 || due[0].Content != "first" || due[1].Content != "second" {
+		t.Errorf("GetDueQueuedNotifications = %+v, want first and second", due)
+	}
+
+	must(t, store.DeleteQueuedNotification(due[0].ID))
+	due, err = store.GetDueQueuedNotifications(1000)
+	must(t, err)
+	if len(due) != 2 {
+		t.Errorf("GetDueQueuedNotifications after deleting returned %d notifications, want 2", len(due))
+	}
+}
+
+func testPermissionGrants(t *testing.T, store database.Store) {
+	must(t, store.SavePermissionGrant(&models.PermissionGrant{GuildID: "g1", TargetType: models.GrantTargetRole, TargetID: "r1", Scope: models.ScopeRead}))
+	must(t, store.SavePermissionGrant(&models.PermissionGrant{GuildID: "g1", TargetType: models.GrantTargetUser, TargetID: "u1", Scope: models.ScopeSettings}))
+	must(t, store.SavePermissionGrant(&models.PermissionGrant{GuildID: "g1", TargetType: models.GrantTargetRole, TargetID: "r1", Scope: models.ScopeNotifications}))
+	must(t, store.SavePermissionGrant(&models.PermissionGrant{GuildID: "g2", TargetType: models.GrantTargetRole, TargetID: "r1", Scope: models.ScopeRead}))
+
+	grants, err := store.GetPermissionGrants("g1")
+	must(t, err)
+	if len(grants) != 2 || grants[0].TargetID != "u1" || grants[1].TargetID != "r1" || grants[1].Scope != models.ScopeNotifications {
+		t.Errorf("GetPermissionGrants = %+v, want u1 then the replaced grant of r1", grants)
+	}
+	if grants[1].CreatedAt == 0 {
+		t.Error("SavePermissionGrant did not set CreatedAt")
+	}
+
+	must(t, store.DeletePermissionGrant("g1", "u1"))
+	if err := store.DeletePermissionGrant("g1", "u1"); !errors.Is(err, database.ErrGrantNotFound) {
+		t.Errorf("deleting a missing grant returned %v, want ErrGrantNotFound", err)
+	}
+
+	must(t, store.DeletePermissionGrantsInGuild("g1"))
+	grants, err = store.GetPermissionGrants("g1")
+	must(t, err)
+	if len(grants) != 0 {
+		t.Errorf("GetPermissionGrants after deleting the guild's grants = %+v", grants)
+	}
+	if grants, _ := store.GetPermissionGrants("g2"); len(grants) != 1 {
+		t.Error("DeletePermissionGrantsInGuild deleted grants of another guild")
+	}
+}
+
+func testImportGuildConfig(t *testing.T, store database.Store) {
+	addUser(t, store, "g1", "c1", "alice")
+	must(t, store.SavePermissionGrant(&models.PermissionGrant{GuildID: "g1", TargetType: models.GrantTargetRole, TargetID: "r1", Scope: models.ScopeRead}))
+
+	users := []models.MonitoredUser{
+		{GuildID: "g1", UserID: "c1", Username: "alice", PostNotificationChannel: "ch2"},
+		{GuildID: "g1", UserID: "c2", Username: "bob"},
+	}
+	settings := &models.GuildSettings{GuildID: "g1", Timezone: "Asia/Tokyo", DigestHour: 7}
+	grants := []models.PermissionGrant{
+		{GuildID: "g1", TargetType: models.GrantTargetRole, TargetID: "r1", Scope: models.ScopeSettings},
+	}
+	must(t, store.ImportGuildConfig(users, settings, grants))
+
+	if got := getUser(t, store, "g1", "c1"); got.PostNotificationChannel != "ch2" || got.PostsEnabled {
+		t.Errorf("imported subscription not saved as a whole: %+v", got)
+	}
+	getUser(t, store, "g1", "c2")
+
+	gotSettings, err := store.GetGuildSettings("g1")
+	must(t, err)
+	if *gotSettings != *settings {
+		t.Errorf("imported settings = %+v, want %+v", gotSettings, settings)
+	}
+	gotGrants, err := store.GetPermissionGrants("g1")
+	must(t, err)
+	if len(gotGrants) != 1 || gotGrants[0].Scope != models.ScopeSettings {
+		t.Errorf("imported grants = %+v, want the settings grant of r1", gotGrants)
+	}
+
+	// Without settings, the stored settings are kept.
+	must(t, store.ImportGuildConfig(nil, nil, nil))
+	gotSettings, err = store.GetGuildSettings("g1")
+	must(t, err)
+	if gotSettings.Timezone != "Asia/Tokyo" {
+		t.Errorf("importing without settings changed them: %+v", gotSettings)
+	}
+}
+
+func testUserSubscriptions(t *testing.T, store database.Store) {
+	must(t, store.AddUserSubscription(&models.UserSubscription{DiscordUserID: "m1", GuildID: "g1", CreatorID: "c1"}))
+	must(t, store.AddUserSubscription(&models.UserSubscription{DiscordUserID: "m1", GuildID: "g1", CreatorID: "c2"}))
+	must(t, store.AddUserSubscription(&models.UserSubscription{DiscordUserID: "m2", GuildID: "g2", CreatorID: "c1"}))
+	if err := store.AddUserSubscription(&models.UserSubscription{DiscordUserID: "m1", GuildID: "g1", CreatorID: "c1"}); err == nil {
+		t.Error("adding a duplicate subscription succeeded")
+	}
+
+	sub, err := store.GetUserSubscription("m1", "g1", "c1")
+	must(t, err)
+	if sub == nil || sub.ID == 0 || sub.CreatedAt == 0 {
+		t.Errorf("GetUserSubscription = %+v, want a stored subscription with ID and CreatedAt", sub)
+	}
+	sub, err = store.GetUserSubscription("m1", "g2", "c1")
+	must(t, err)
+	if sub != nil {
+		t.Errorf("GetUserSubscription for a missing subscription = %+v, want nil", sub)
+	}
+
+	subs, err := store.GetUserSubscriptions("m1")
+	must(t, err)
+	if len(subs) != 2 || subs[0].CreatorID != "c1" {
+		t.Errorf("GetUserSubscriptions = %+v, want c1 and c2 in order", subs)
+	}
+	count, err := store.CountUserSubscriptions("m1")
+	must(t, err)
+	if count != 2 {
+		t.Errorf("CountUserSubscriptions = %d, want 2", count)
+	}
+	subs, err = store.GetSubscribersForCreator("c1")
+	must(t, err)
+	if len(subs) != 2 {
+		t.Errorf("GetSubscribersForCreator returned %d subscriptions, want 2", len(subs))
+	}
+
+	must(t, store.UpdateUserSubscriptionFailures("m1", 2))
+	if sub, _ := store.GetUserSubscription("m1", "g1", "c2"); sub == nil || sub.Failures != 2 {
+		t.Errorf("UpdateUserSubscriptionFailures not applied: %+v", sub)
+	}
+
+	must(t, store.DeleteUserSubscription("m1", "g1", "c1"))
+	if err := store.DeleteUserSubscription("m1", "g1", "c1"); !errors.Is(err, database.ErrSubscriptionNotFound) {
+		t.Errorf("deleting a missing subscription returned %v, want ErrSubscriptionNotFound", err)
+	}
+	must(t, store.DeleteUserSubscriptionsForCreator("g2", "c1"))
+	must(t, store.DeleteUserSubscriptionsForDiscordUser("m1"))
+	must(t, store.DeleteUserSubscriptionsInGuild("g1"))
+	for _, member := range []string{"m1", "m2"} {
+		if count, _ := store.CountUserSubscriptions(member); count != 0 {
+			t.Errorf("%s still has %d subscriptions", member, count)
+		}
+	}
+}
+
+func testChannelWebhooks(t *testing.T, store database.Store) {
+	webhook, err := store.GetChannelWebhook("ch1")
+	must(t, err)
+	if webhook != nil {
+		t.Errorf("GetChannelWebhook before saving = %+v, want nil", webhook)
+	}
+
+	must(t, store.SaveChannelWebhook(&models.ChannelWebhook{ChannelID: "ch1", GuildID: "g1", WebhookID: "w1", Token: "t1"}))
+	must(t, store.SaveChannelWebhook(&models.ChannelWebhook{ChannelID: "ch1", GuildID: "g1", WebhookID: "w2", Token: "t2"}))
+	webhook, err = store.GetChannelWebhook("ch1")
+	must(t, err)
+	if webhook == nil || webhook.WebhookID != "w2" {
+		t.Errorf("GetChannelWebhook = %+v, want the replaced webhook w2", webhook)
+	}
+
+	must(t, store.DeleteChannelWebhook("ch1"))
+	if webhook, _ := store.GetChannelWebhook("ch1"); webhook != nil {
+		t.Errorf("GetChannelWebhook after deleting = %+v, want nil", webhook)
+	}
+}
+
+func testLiveEvents(t *testing.T, store database.Store) {
+	must(t, store.SaveLiveEvent(&models.LiveEvent{GuildID: "g1", UserID: "c1", EventID: "e1"}))
+	must(t, store.SaveLiveEvent(&models.LiveEvent{GuildID: "g2", UserID: "c1", EventID: "e2", CreatedAt: 5}))
+	must(t, store.SaveLiveEvent(&models.LiveEvent{GuildID: "g1", UserID: "c1", EventID: "e3", CreatedAt: 6}))
+
+	event, err := store.GetLiveEvent("g1", "c1")
+	must(t, err)
+	if event == nil || event.EventID != "e3" {
+		t.Errorf("GetLiveEvent = %+v, want the replaced event e3", event)
+	}
+	events, err := store.GetLiveEvents()
+	must(t, err)
+	if len(events) != 2 {
+		t.Errorf("GetLiveEvents returned %d events, want 2", len(events))
+	}
+
+	must(t, store.DeleteLiveEvent("g1", "c1"))
+	event, err = store.GetLiveEvent("g1", "c1")
+	must(t, err)
+	if event != nil {
+		t.Errorf("GetLiveEvent after deleting = %+v, want nil", event)
+	}
+}
+
+func testCreatorHealth(t *testing.T, store database.Store) {
+	must(t, store.SaveCreatorHealth([]models.CreatorHealth{
+		{CreatorID: "c1", LastCheckAt: 10},
+		{CreatorID: "c2", LastCheckAt: 20},
+	}))
+	must(t, store.SaveCreatorHealth([]models.CreatorHealth{
+		{CreatorID: "c1", LastCheckAt: 30, ConsecutiveErrors: 2, LastError: "boom"},
+	}))
+
+	health, err := store.GetAllCreatorHealth()
+	must(t, err)
+	byID := make(map[string]models.CreatorHealth)
+	for _, h := range health {
+		byID[h.CreatorID] = h
+	}
+	if len(health) != 2 || byID["c1"].LastCheckAt != 30 || byID["c1"].LastError != "boom" || byID["c2"].LastCheckAt != 20 {
+		t.Errorf("GetAllCreatorHealth = %+v", health)
+	}
+
+	must(t, store.DeleteCreatorHealth("c1"))
+	health, err = store.GetAllCreatorHealth()
+	must(t, err)
+	if len(health) != 1 || health[0].CreatorID != "c2" {
+		t.Errorf("GetAllCreatorHealth after deleting = %+v, want only c2", health)
+	}
+}
+
+func testUnmonitoredCreators(t *testing.T, store database.Store) {
+	must(t, store.MarkCreatorUnmonitored("c2", 200))
+	must(t, store.MarkCreatorUnmonitored("c1", 100))
+	must(t, store.MarkCreatorUnmonitored("c1", 300))
+
+	creators, err := store.GetUnmonitoredCreators()
+	must(t, err)
+	want := []models.UnmonitoredCreator{{CreatorID: "c1", Since: 100}, {CreatorID: "c2", Since: 200}}
+	if !slices.Equal(creators, want) {
+		t.Errorf("GetUnmonitoredCreators = %+v, want %+v", creators, want)
+	}
+
+	must(t, store.DeleteUnmonitoredCreator("c1"))
+	creators, err = store.GetUnmonitoredCreators()
+	must(t, err)
+	if len(creators) != 1 || creators[0].CreatorID != "c2" {
+		t.Errorf("GetUnmonitoredCreators after deleting c1 = %+v, want only c2", creators)
+	}
+}
+
+func testWebhookEndpoints(t *testing.T, store database.Store) {
+	first := &models.WebhookEndpoint{GuildID: "g1", URL: "https://example.com/1"}
+	second := &models.WebhookEndpoint{GuildID: "g1", URL: "https://example.com/2"}
+	global := &models.WebhookEndpoint{URL: "https://example.com/global"}
+	for _, endpoint := range []*models.WebhookEndpoint{first, second, global} {
+		must(t, store.AddWebhookEndpoint(endpoint))
+		if endpoint.ID == 0 || endpoint.CreatedAt == 0 {
+			t.Fatalf("AddWebhookEndpoint did not set ID and CreatedAt: %+v", endpoint)
+		}
+	}
+
+	endpoints, err := store.GetWebhookEndpointsForGuild("g1")
+	must(t, err)
+	if len(endpoints) != 2 || endpoints[0].ID != first.ID || endpoints[1].ID != second.ID {
+		t.Errorf("GetWebhookEndpointsForGuild = %+v, want both endpoints in order", endpoints)
+	}
+	endpoints, err = store.GetWebhookEndpointsForGuild("")
+	must(t, err)
+	if len(endpoints) != 1 || endpoints[0].ID != global.ID {
+		t.Errorf("global endpoints = %+v, want only the global endpoint", endpoints)
+	}
+	count, err := store.CountWebhookEndpointsForGuild("g1")
+	must(t, err)
+	if count != 2 {
+		t.Errorf("CountWebhookEndpointsForGuild = %d, want 2", count)
+	}
+
+	endpoint, err := store.GetWebhookEndpoint("g2", first.ID)
+	must(t, err)
+	if endpoint != nil {
+		t.Errorf("GetWebhookEndpoint from another guild = %+v, want nil", endpoint)
+	}
+
+	must(t, store.AddWebhookDeadLetter(&models.WebhookDeadLetter{EndpointID: first.ID, Event: "post.created"}))
+	must(t, store.AddWebhookDeadLetter(&models.WebhookDeadLetter{EndpointID: first.ID, Event: "live.started"}))
+	count, err = store.CountWebhookDeadLetters(first.ID)
+	must(t, err)
+	if count != 2 {
+		t.Errorf("CountWebhookDeadLetters = %d, want 2", count)
+	}
+
+	if err := store.DeleteWebhookEndpoint("g2", first.ID); !errors.Is(err, database.ErrEndpointNotFound) {
+		t.Errorf("deleting an endpoint of another guild returned %v, want ErrEndpointNotFound", err)
+	}
+	must(t, store.DeleteWebhookEndpoint("g1", first.ID))
+	count, err = store.CountWebhookDeadLetters(first.ID)
+	must(t, err)
+	if count != 0 {
+		t.Errorf("dead letters of a deleted endpoint kept: %d", count)
+	}
+}
@@ -0,0 +1,32 @@
+package database
+
+import (
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+// MarkCreatorUnmonitored records that no guild monitors a creator anymore,
+// keeping the earliest time if already marked
+func (r *Repository) MarkCreatorUnmonitored(creatorID string, at int64) error {
+	return WithRetry(func() error {
+		return r.db.Where(models.UnmonitoredCreator{CreatorID: creatorID}).
+			Attrs(models.UnmonitoredCreator{Since: at}).
+			FirstOrCreate(&models.UnmonitoredCreator{}).Error
+	})
+}
+
+// GetUnmonitoredCreators returns every creator marked as unmonitored, oldest first
+func (r *Repository) GetUnmonitoredCreators() ([]models.UnmonitoredCreator, error) {
+	var creators []models.UnmonitoredCreator
+	err := WithRetry(func() error {
+		return r.db.Order("since ASC, creator_id ASC").Find(&creators).Error
+	})
+	return creators, err
+}
+
+// DeleteUnmonitoredCreator removes the mark, after an unfollow or when a guild
+// monitors the creator again
+func (r *Repository) DeleteUnmonitoredCreator(creatorID string) error {
+	return WithRetry(func() error {
+		return r.db.Delete(&models.UnmonitoredCreator{}, "creator_id = ?", creatorID).Error
+	})
+}
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+	"gorm.io/gorm"
+)
+
+func (r *Repository) AddUserSubscription(sub *models.UserSubscription) error {
+	return WithRetry(func() error {
+		return r.db.Create(sub).Error
+	})
+}
+
+// GetUserSubscription returns a member's subscription to a creator in a guild, or nil if there is none
+func (r *Repository) GetUserSubscription(discordUserID, guildID, creatorID string) (*models.UserSubscription, error) {
+	var sub models.UserSubscription
+	err := WithRetry(func() error {
+		return r.db.Where("discord_user_id = ? AND guild_id = ? AND creator_id = ?", discordUserID, guildID, creatorID).First(&sub).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &sub, err
+}
+
+// GetUserSubscriptions returns every subscription a member has, across all guilds
+func (r *Repository) GetUserSubscriptions(discordUserID string) ([]models.UserSubscription, error) {
+	var subs []models.UserSubscription
+	err := WithRetry(func() error {
+		return r.db.Where("discord_user_id = ?", discordUserID).Order("id").Find(&subs).Error
+	})
+	return subs, err
+}
+
+func (r *Repository) CountUserSubscriptions(discordUserID string) (int64, error) {
+	var count int64
+	err := WithRetry(func() error {
+		return r.db.Model(&models.UserSubscription{}).Where("discord_user_id = ?", discordUserID).Count(&count).Error
+	})
+	return count, err
+}
+
+// GetSubscribersForCreator returns every member subscription to a creator, across all guilds
+func (r *Repository) GetSubscribersForCreator(creatorID string) ([]models.UserSubscription, error) {
+	var subs []models.UserSubscription
+	err := WithRetry(func() error {
+		return r.db.Where("creator_id = ?", creatorID).Order("id").Find(&subs).Error
+	})
+	return subs, err
+}
+
+func (r *Repository) DeleteUserSubscription(discordUserID, guildID, creatorID string) error {
+	return WithRetry(func() error {
+		result := r.db.Delete(&models.UserSubscription{}, "discord_user_id = ? AND guild_id = ? AND creator_id = ?", discordUserID, guildID, creatorID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrSubscriptionNotFound
+		}
+		return nil
+	})
+}
+
+// DeleteUserSubscriptionsForDiscordUser removes every subscription of a member, used once DMs keep failing
+func (r *Repository) DeleteUserSubscriptionsForDiscordUser(discordUserID string) error {
+	return WithRetry(func() error {
+		return r.db.Delete(&models.UserSubscription{}, "discord_user_id = ?", discordUserID).Error
+	})
+}
+
+// DeleteUserSubscriptionsForCreator removes the member subscriptions to a creator once the guild stops monitoring it
+func (r *Repository) DeleteUserSubscriptionsForCreator(guildID, creatorID string) error {
+	return WithRetry(func() error {
+		return r.db.Delete(&models.UserSubscription{}, "guild_id = ? AND creator_id = ?", guildID, creatorID).Error
+	})
+}
+
+func (r *Repository) DeleteUserSubscriptionsInGuild(guildID string) error {
+	return WithRetry(func() error {
+		return r.db.Delete(&models.UserSubscription{}, "guild_id = ?", guildID).Error
+	})
+}
+
+// UpdateUserSubscriptionFailures sets the consecutive failure count on all of a member's subscriptions
+func (r *Repository) UpdateUserSubscriptionFailures(discordUserID string, failures int) error {
+	return WithRetry(func() error {
+		return r.db.Model(&models.UserSubscription{}).
+			Where("discord_user_id = ?", discordUserID).
+			Update("failures", failures).Error
+	})
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+	"gorm.io/gorm"
+)
+
+// AddWebhookEndpoint registers a new outbound endpoint
+func (r *Repository) AddWebhookEndpoint(endpoint *models.WebhookEndpoint) error {
+	return WithRetry(func() error {
+		return r.db.Create(endpoint).Error
+	})
+}
+
+// GetWebhookEndpointsForGuild returns the endpoints registered for a guild. An empty guild ID returns the global endpoints.
+func (r *Repository) GetWebhookEndpointsForGuild(guildID string) ([]models.WebhookEndpoint, error) {
+	var endpoints []models.WebhookEndpoint
+	err := WithRetry(func() error {
+		return r.db.Where("guild_id = ?", guildID).Order("id").Find(&endpoints).Error
+	})
+	return endpoints, err
+}
+
+// GetWebhookEndpoint returns an endpoint by ID if it belongs to the guild, or nil if there is none
+func (r *Repository) GetWebhookEndpoint(guildID string, id uint) (*models.WebhookEndpoint, error) {
+	var endpoint models.WebhookEndpoint
+	err := WithRetry(func() error {
+		return r.db.Where("guild_id = ? AND id = ?", guildID, id).First(&endpoint).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &endpoint, err
+}
+
+func (r *Repository) CountWebhookEndpointsForGuild(guildID string) (int64, error) {
+	var count int64
+	err := WithRetry(func() error {
+		return r.db.Model(&models.WebhookEndpoint{}).Where("guild_id = ?", guildID).Count(&count).Error
+	})
+	return count, err
+}
+
+// DeleteWebhookEndpoint removes an endpoint belonging to the guild along with its dead letters
+func (r *Repository) DeleteWebhookEndpoint(guildID string, id uint) error {
+	return WithRetry(func() error {
+		result := r.db.Delete(&models.WebhookEndpoint{}, "guild_id = ? AND id = ?", guildID, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrEndpointNotFound
+		}
+		return r.db.Delete(&models.WebhookDeadLetter{}, "endpoint_id = ?", id).Error
+	})
+}
+
+// AddWebhookDeadLetter records a payload that could not be delivered
+func (r *Repository) AddWebhookDeadLetter(deadLetter *models.WebhookDeadLetter) error {
+	return WithRetry(func() error {
+		return r.db.Create(deadLetter).Error
+	})
+}
+
+func (r *Repository) CountWebhookDeadLetters(endpointID uint) (int64, error) {
+	var count int64
+	err := WithRetry(func() error {
+		return r.db.Model(&models.WebhookDeadLetter{}).Where("endpoint_id = ?", endpointID).Count(&count).Error
+	})
+	return count, err
+}
@@ -3,10 +3,17 @@ package embed
 import (
 	"fmt"
 	//"log"
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/fvckgrimm/discord-fansly-notify/api"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+const (
+	maxDigestEntries   = 20
+	maxDigestTitleRune = 80
 )
 
 func CreateLiveStreamEmbed(username string, streamInfo *api.StreamResponse, avatarLocation string, liveImageURL string) *discordgo.MessageEmbed {
@@ -81,3 +88,55 @@ func CreatePostEmbed(username string, post api.Post, avatarLocation string, post
 
 	return embed
 }
+
+func CreateDigestEmbed(username string, posts []models.PendingPost, avatarLocation string, mode string) *discordgo.MessageEmbed {
+	creatorUrl := fmt.Sprintf("https://fansly.com/%s", username)
+
+	var lines []string
+	for idx, post := range posts {
+		if idx == maxDigestEntries {
+			lines = append(lines, fmt.Sprintf("…and %d more", len(posts)-maxDigestEntries))
+			break
+		}
+		postURL := fmt.Sprintf("https://fans.ly/post/%s", post.PostID)
+		lines = append(lines, fmt.Sprintf("• [%s](%s) — <t:%d:R>", digestTitle(post.Content), postURL, post.PostedAt))
+	}
+
+	noun := "posts"
+	if len(posts) == 1 {
+		noun = "post"
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("%d new %s from %s", len(posts), noun, username),
+		URL:         creatorUrl,
+		Color:       0x03b2f8,
+		Description: strings.Join(lines, "\n"),
+		Author: &discordgo.MessageEmbedAuthor{
+			URL:     creatorUrl,
+			Name:    username,
+			IconURL: avatarLocation,
+		},
+		Thumbnail: &discordgo.MessageEmbedThumbnail{
+			URL: avatarLocation,
+		},
+		Footer: &discordgo.MessageEmbedFooter{
+			Text: fmt.Sprintf("%s digest", strings.ToUpper(mode[:1])+mode[1:]),
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+}
+
+// digestTitle uses the first line of a post as its title, shortened to fit a digest entry.
+func digestTitle(content string) string {
+	title := strings.TrimSpace(strings.SplitN(content, "\n", 2)[0])
+	if title == "" {
+		return "Untitled post"
+	}
+	// Square brackets would break the markdown link.
+	title = strings.NewReplacer("[", "(", "]", ")").Replace(title)
+	if runes := []rune(title); len(runes) > maxDigestTitleRune {
+		title = string(runes[:maxDigestTitleRune-1]) + "…"
+	}
+	return title
+}
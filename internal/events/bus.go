@@ -0,0 +1,57 @@
+package events
+
+import (
+	"log"
+	"sync"
+)
+
+// Handler receives every event published on a bus.
+type Handler func(Event)
+
+// Bus delivers events to subscribers synchronously, in subscription order.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers a handler for all events.
+func (b *Bus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish hands the event to every subscriber. A panicking subscriber is
+// logged and does not prevent the remaining subscribers from running.
+func (b *Bus) Publish(event Event) {
+	b.mu.RLock()
+	handlers := make([]Handler, len(b.handlers))
+	copy(handlers, b.handlers)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		dispatch(handler, event)
+	}
+}
+
+func dispatch(handler Handler, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Event subscriber panicked handling %s: %v", event.Name(), r)
+		}
+	}()
+	handler(event)
+}
+
+// On subscribes fn to events of type T only.
+func On[T Event](bus *Bus, fn func(T)) {
+	bus.Subscribe(func(event Event) {
+		if typed, ok := event.(T); ok {
+			fn(typed)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+// Package events is a small synchronous publish/subscribe bus. The monitoring
+// loop publishes what it detected about a creator and subscribers decide what
+// to do with it, so delivery targets can be added without touching detection.
+package events
+
+import (
+	"github.com/fvckgrimm/discord-fansly-notify/api"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+// Event is implemented by every event published on the bus.
+type Event interface {
+	Name() string
+}
+
+// Events are published once per creator. Subscriptions holds the guild rows
+// the event applies to, with their state already updated by the detector.
+
+type PostPublished struct {
+	CreatorID     string
+	Post          api.Post
+	Subscriptions []models.MonitoredUser
+}
+
+type StreamStarted struct {
+	CreatorID     string
+	Stream        *api.StreamResponse
+	Subscriptions []models.MonitoredUser
+}
+
+type StreamEnded struct {
+	CreatorID     string
+	StartedAt     int64
+	Subscriptions []models.MonitoredUser
+}
+
+type AvatarChanged struct {
+	CreatorID     string
+	OldAvatar     string
+	NewAvatar     string
+	Subscriptions []models.MonitoredUser
+}
+
+type UsernameChanged struct {
+	CreatorID     string
+	OldUsername   string
+	NewUsername   string
+	Subscriptions []models.MonitoredUser
+}
+
+func (PostPublished) Name() string   { return "post_published" }
+func (StreamStarted) Name() string   { return "stream_started" }
+func (StreamEnded) Name() string     { return "stream_ended" }
+func (AvatarChanged) Name() string   { return "avatar_changed" }
+func (UsernameChanged) Name() string { return "username_changed" }
@@ -0,0 +1,14 @@
+package models
+
+// ChannelWebhook stores the webhook the bot created in a channel so it can be
+// reused for creator-identity notifications.
+type ChannelWebhook struct {
+	ChannelID string `gorm:"primaryKey;column:channel_id"`
+	GuildID   string `gorm:"column:guild_id"`
+	WebhookID string `gorm:"column:webhook_id"`
+	Token     string `gorm:"column:token"`
+}
+
+func (ChannelWebhook) TableName() string {
+	return "channel_webhooks"
+}
@@ -0,0 +1,13 @@
+package models
+
+// CreatorAlias is a username a creator used before renaming. Commands accept
+// aliases so members can keep using the name they know.
+type CreatorAlias struct {
+	UserID     string `gorm:"primaryKey;column:user_id"`
+	Username   string `gorm:"primaryKey;column:username"`
+	ReplacedAt int64  `gorm:"column:replaced_at"`
+}
+
+func (CreatorAlias) TableName() string {
+	return "creator_aliases"
+}
@@ -0,0 +1,22 @@
+package models
+
+// CreatorHealth summarises the latest monitoring checks of a creator. It is
+// shared by every guild monitoring the creator; delivery errors are kept per
+// guild on MonitoredUser.
+type CreatorHealth struct {
+	CreatorID     string `gorm:"primaryKey;column:creator_id"`
+	LastCheckAt   int64  `gorm:"column:last_check_at"`
+	LastSuccessAt int64  `gorm:"column:last_success_at"`
+	// LastPostAt is when the newest post seen on the timeline was created.
+	LastPostAt int64 `gorm:"column:last_post_at"`
+	// TimelineCheckedAt is zero until the timeline has been read once.
+	TimelineCheckedAt  int64  `gorm:"column:timeline_checked_at"`
+	TimelineAccessible bool   `gorm:"column:timeline_accessible"`
+	ConsecutiveErrors  int    `gorm:"column:consecutive_errors"`
+	LastError          string `gorm:"column:last_error"`
+	LastErrorAt        int64  `gorm:"column:last_error_at"`
+}
+
+func (CreatorHealth) TableName() string {
+	return "creator_health"
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Default values used when a guild has not stored any settings yet.
+const (
+	DefaultTimezone   = "UTC"
+	DefaultDigestHour = 9
+)
+
+// Quiet hour behaviours. Suppress still delivers notifications but without
+// role mentions, queue holds them back until the window ends.
+const (
+	QuietModeSuppress = "suppress"
+	QuietModeQueue    = "queue"
+)
+
+type GuildSettings struct {
+	GuildID    string `gorm:"primaryKey;column:guild_id"`
+	Timezone   string `gorm:"column:timezone"`
+	DigestHour int    `gorm:"column:digest_hour"`
+	// Quiet hours are stored as minutes after midnight in the guild timezone.
+	QuietHoursEnabled bool   `gorm:"column:quiet_hours_enabled"`
+	QuietStart        int    `gorm:"column:quiet_start"`
+	QuietEnd          int    `gorm:"column:quiet_end"`
+	QuietMode         string `gorm:"column:quiet_mode"`
+	// ScheduledEventsEnabled mirrors live streams as Discord scheduled events.
+	ScheduledEventsEnabled bool `gorm:"column:scheduled_events_enabled"`
+	// AnnounceRenames posts a message when a monitored creator changes username.
+	AnnounceRenames bool `gorm:"column:announce_renames"`
+}
+
+func (GuildSettings) TableName() string {
+	return "guild_settings"
+}
+
+// Location returns the guild's configured timezone, falling back to UTC
+// when the stored name is empty or no longer valid.
+func (g GuildSettings) Location() *time.Location {
+	if g.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(g.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,14 @@
+package models
+
+// LiveEvent links a creator's current stream to the Discord scheduled event
+// created for it, so the event can be ended later, even after a restart.
+type LiveEvent struct {
+	GuildID   string `gorm:"primaryKey;column:guild_id"`
+	UserID    string `gorm:"primaryKey;column:user_id"`
+	EventID   string `gorm:"column:event_id"`
+	CreatedAt int64  `gorm:"column:created_at"`
+}
+
+func (LiveEvent) TableName() string {
+	return "live_events"
+}
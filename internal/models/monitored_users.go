@@ -1,5 +1,13 @@
 package models
 
+// Delivery modes for post notifications. Live notifications are always
+// delivered instantly.
+const (
+	DeliveryInstant = "instant"
+	DeliveryHourly  = "hourly"
+	DeliveryDaily   = "daily"
+)
+
 type MonitoredUser struct {
 	GuildID                 string `gorm:"primaryKey;column:guild_id"`
 	UserID                  string `gorm:"primaryKey;column:user_id"`
@@ -17,6 +25,22 @@ type MonitoredUser struct {
 	LiveEnabled             bool   `gorm:"column:live_enabled"`
 	LiveMentionRole         string `gorm:"column:live_mention_role"`
 	PostMentionRole         string `gorm:"column:post_mention_role"`
+	PostDeliveryMode        string `gorm:"column:post_delivery_mode"`
+	MutedUntil              int64  `gorm:"column:muted_until"`
+	WebhookDelivery         bool   `gorm:"column:webhook_delivery"`
+	IsLive                  bool   `gorm:"column:is_live"`
+	ThreadLive              bool   `gorm:"column:thread_live"`
+	ThreadPosts             bool   `gorm:"column:thread_posts"`
+	AutoCrosspost           bool   `gorm:"column:auto_crosspost"`
+	NotifyButtons           bool   `gorm:"column:notify_buttons"`
+	NotifyRoleID            string `gorm:"column:notify_role_id"`
+	LastDeliveryError       string `gorm:"column:last_delivery_error"`
+	LastDeliveryErrorAt     int64  `gorm:"column:last_delivery_error_at"`
+
+	// PostsSuspended pauses enabled post notifications while the bot has no
+	// access to the timeline. The pause is lifted once the timeline can be
+	// read again.
+	PostsSuspended bool `gorm:"column:posts_suspended"`
 }
 
 type SchemaVersion struct {
@@ -26,3 +50,12 @@ type SchemaVersion struct {
 func (MonitoredUser) TableName() string {
 	return "monitored_users"
 }
+
+// DeliveryMode returns the post delivery mode, treating rows created before
+// digest support as instant.
+func (u MonitoredUser) DeliveryMode() string {
+	if u.PostDeliveryMode == "" {
+		return DeliveryInstant
+	}
+	return u.PostDeliveryMode
+}
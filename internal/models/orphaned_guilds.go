@@ -0,0 +1,12 @@
+package models
+
+// OrphanedGuild marks a guild the bot was removed from. Its data is kept, but
+// not monitored, until the bot rejoins or the retention period ends.
+type OrphanedGuild struct {
+	GuildID    string `gorm:"primaryKey;column:guild_id"`
+	OrphanedAt int64  `gorm:"column:orphaned_at"`
+}
+
+func (OrphanedGuild) TableName() string {
+	return "orphaned_guilds"
+}
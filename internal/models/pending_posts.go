@@ -0,0 +1,17 @@
+package models
+
+// PendingPost is a post detected for a subscription in digest mode that has
+// not been included in a summary yet.
+type PendingPost struct {
+	ID       uint   `gorm:"primaryKey;autoIncrement;column:id"`
+	GuildID  string `gorm:"column:guild_id"`
+	UserID   string `gorm:"column:user_id"`
+	PostID   string `gorm:"column:post_id"`
+	Content  string `gorm:"column:content"`
+	PostedAt int64  `gorm:"column:posted_at"`
+	QueuedAt int64  `gorm:"column:queued_at"`
+}
+
+func (PendingPost) TableName() string {
+	return "pending_posts"
+}
@@ -0,0 +1,45 @@
+package models
+
+// Permission scopes that can be granted to roles or members. Scopes are
+// ordered: settings includes notifications, which includes read.
+const (
+	ScopeRead          = "read"
+	ScopeNotifications = "notifications"
+	ScopeSettings      = "settings"
+)
+
+// Grant target types.
+const (
+	GrantTargetRole = "role"
+	GrantTargetUser = "user"
+)
+
+// PermissionGrant gives a role or member bot-management rights in a guild.
+type PermissionGrant struct {
+	ID         uint   `gorm:"primaryKey;autoIncrement;column:id"`
+	GuildID    string `gorm:"column:guild_id"`
+	TargetType string `gorm:"column:target_type"`
+	TargetID   string `gorm:"column:target_id"`
+	Scope      string `gorm:"column:scope"`
+	GrantedBy  string `gorm:"column:granted_by"`
+	CreatedAt  int64  `gorm:"column:created_at"`
+}
+
+func (PermissionGrant) TableName() string {
+	return "permission_grants"
+}
+
+// ScopeLevel orders scopes so a grant can be compared with a requirement.
+// Unknown scopes have level 0 and never satisfy anything.
+func ScopeLevel(scope string) int {
+	switch scope {
+	case ScopeRead:
+		return 1
+	case ScopeNotifications:
+		return 2
+	case ScopeSettings:
+		return 3
+	default:
+		return 0
+	}
+}
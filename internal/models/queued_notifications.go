@@ -0,0 +1,19 @@
+package models
+
+// QueuedNotification is a notification held back by quiet hours. The embed
+// is stored as JSON so it can be sent unchanged once ReleaseAt has passed.
+type QueuedNotification struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement;column:id"`
+	GuildID   string `gorm:"column:guild_id"`
+	UserID    string `gorm:"column:user_id"`
+	Kind      string `gorm:"column:kind"`
+	ChannelID string `gorm:"column:channel_id"`
+	Content   string `gorm:"column:content"`
+	Embed     string `gorm:"column:embed"`
+	ReleaseAt int64  `gorm:"column:release_at"`
+	CreatedAt int64  `gorm:"column:created_at"`
+}
+
+func (QueuedNotification) TableName() string {
+	return "queued_notifications"
+}
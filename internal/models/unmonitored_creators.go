@@ -0,0 +1,13 @@
+package models
+
+// UnmonitoredCreator marks a creator no guild monitors anymore. The bot's
+// account unfollows them once the grace period has passed, unless a guild
+// adds them again first.
+type UnmonitoredCreator struct {
+	CreatorID string `gorm:"primaryKey;column:creator_id"`
+	Since     int64  `gorm:"column:since"`
+}
+
+func (UnmonitoredCreator) TableName() string {
+	return "unmonitored_creators"
+}
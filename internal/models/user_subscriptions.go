@@ -0,0 +1,16 @@
+package models
+
+// UserSubscription is a member's personal DM subscription to a creator that
+// their guild monitors. Failures counts consecutive DMs that could not be delivered.
+type UserSubscription struct {
+	ID            uint   `gorm:"primaryKey;autoIncrement;column:id"`
+	DiscordUserID string `gorm:"column:discord_user_id"`
+	GuildID       string `gorm:"column:guild_id"`
+	CreatorID     string `gorm:"column:creator_id"`
+	Failures      int    `gorm:"column:failures"`
+	CreatedAt     int64  `gorm:"column:created_at"`
+}
+
+func (UserSubscription) TableName() string {
+	return "user_subscriptions"
+}
@@ -0,0 +1,34 @@
+package models
+
+// WebhookEndpoint is an outbound HTTP endpoint that receives signed JSON
+// events. Endpoints with an empty GuildID were registered by the bot owner
+// and receive events from every guild.
+type WebhookEndpoint struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement;column:id"`
+	GuildID   string `gorm:"column:guild_id;index"`
+	URL       string `gorm:"column:url"`
+	Secret    string `gorm:"column:secret"`
+	CreatedBy string `gorm:"column:created_by"`
+	CreatedAt int64  `gorm:"column:created_at"`
+}
+
+func (WebhookEndpoint) TableName() string {
+	return "webhook_endpoints"
+}
+
+// WebhookDeadLetter records a payload that could not be delivered to an
+// endpoint after all retries.
+type WebhookDeadLetter struct {
+	ID         uint   `gorm:"primaryKey;autoIncrement;column:id"`
+	EndpointID uint   `gorm:"column:endpoint_id;index"`
+	URL        string `gorm:"column:url"`
+	Event      string `gorm:"column:event"`
+	Payload    string `gorm:"column:payload"`
+	Error      string `gorm:"column:error"`
+	Attempts   int    `gorm:"column:attempts"`
+	CreatedAt  int64  `gorm:"column:created_at"`
+}
+
+func (WebhookDeadLetter) TableName() string {
+	return "webhook_dead_letters"
+}
@@ -0,0 +1,208 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Headers sent with every request. The signature is an HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the endpoint secret, hex encoded and
+// prefixed with "sha256=".
+const (
+	HeaderEvent     = "X-Fansly-Notify-Event"
+	HeaderTimestamp = "X-Fansly-Notify-Timestamp"
+	HeaderSignature = "X-Fansly-Notify-Signature"
+)
+
+const (
+	defaultMaxAttempts = 3
+	defaultBackoff     = 2 * time.Second
+	requestTimeout     = 10 * time.Second
+)
+
+var errPrivateAddress = errors.New("destination resolves to a private or loopback address")
+
+// HTTPSink POSTs signed JSON payloads to a URL.
+type HTTPSink struct {
+	URL         string
+	Secret      string
+	Client      *http.Client
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+// DeliveryError is returned once every attempt to deliver a payload has failed.
+type DeliveryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *DeliveryError) Error() string {
+	return fmt.Sprintf("delivery failed after %d attempts: %v", e.Attempts, e.Err)
+}
+
+func (e *DeliveryError) Unwrap() error {
+	return e.Err
+}
+
+// permanentError marks failures that retrying will not fix, such as a 4xx response.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+// NewHTTPSink creates a sink for url. Unless allowPrivate is set, connections
+// to loopback, private and link-local addresses are refused so guild
+// endpoints cannot be pointed at the host's internal network.
+func NewHTTPSink(url, secret string, allowPrivate bool) *HTTPSink {
+	return &HTTPSink{
+		URL:         url,
+		Secret:      secret,
+		Client:      newHTTPClient(allowPrivate),
+		MaxAttempts: defaultMaxAttempts,
+		Backoff:     defaultBackoff,
+	}
+}
+
+func newHTTPClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: requestTimeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip != nil && isPrivateIP(ip) {
+				return errPrivateAddress
+			}
+			return nil
+		}
+	}
+
+	return &http.Client{
+		Timeout:   requestTimeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext, Proxy: http.ProxyFromEnvironment},
+		// Redirects could bypass the address check and are never expected from a webhook receiver.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func isPrivateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified()
+}
+
+// ValidateURL checks that an endpoint URL is usable before it is stored.
+func ValidateURL(raw string, allowPrivate bool) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("%q is not a valid URL", raw)
+	}
+	if u.Scheme != "https" && !(allowPrivate && u.Scheme == "http") {
+		return fmt.Errorf("endpoint URLs must use https")
+	}
+	if allowPrivate {
+		return nil
+	}
+
+	ips, err := net.LookupIP(u.Hostname())
+	if err != nil {
+		return fmt.Errorf("could not resolve %s: %v", u.Hostname(), err)
+	}
+	for _, ip := range ips {
+		if isPrivateIP(ip) {
+			return errPrivateAddress
+		}
+	}
+	return nil
+}
+
+// Sign computes the signature header value for a request body.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send delivers the payload, retrying network errors, 429s and 5xx responses with linear backoff.
+func (s *HTTPSink) Send(ctx context.Context, payload Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	attempts := max(1, s.MaxAttempts)
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		lastErr = s.post(ctx, payload.Event, body)
+		if lastErr == nil {
+			return nil
+		}
+
+		var perm permanentError
+		if errors.As(lastErr, &perm) {
+			return &DeliveryError{Attempts: attempt, Err: perm.err}
+		}
+
+		if attempt < attempts {
+			select {
+			case <-time.After(s.Backoff * time.Duration(attempt)):
+			case <-ctx.Done():
+				return &DeliveryError{Attempts: attempt, Err: ctx.Err()}
+			}
+		}
+	}
+	return &DeliveryError{Attempts: attempts, Err: lastErr}
+}
+
+func (s *HTTPSink) post(ctx context.Context, event string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return permanentError{err}
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "discord-fansly-notify")
+	req.Header.Set(HeaderEvent, event)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(s.Secret, timestamp, body))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		if errors.Is(err, errPrivateAddress) {
+			return permanentError{err}
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	default:
+		return permanentError{fmt.Errorf("endpoint responded with status %d", resp.StatusCode)}
+	}
+}
@@ -0,0 +1,114 @@
+// Package sink delivers monitoring events to destinations other than Discord
+// channels, such as HTTP endpoints registered by guilds or the bot owner.
+package sink
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fvckgrimm/discord-fansly-notify/api"
+	"github.com/fvckgrimm/discord-fansly-notify/internal/models"
+)
+
+// PayloadVersion is bumped whenever the payload shape changes incompatibly.
+const PayloadVersion = 1
+
+// Event types carried in Payload.Event.
+const (
+	EventPostPublished = "post.published"
+	EventStreamStarted = "stream.started"
+	EventStreamEnded   = "stream.ended"
+	EventTest          = "test"
+)
+
+// Sink is a destination for event payloads.
+type Sink interface {
+	Send(ctx context.Context, payload Payload) error
+}
+
+type Payload struct {
+	Version   int     `json:"version"`
+	Event     string  `json:"event"`
+	Timestamp int64   `json:"timestamp"`
+	GuildID   string  `json:"guild_id,omitempty"`
+	Creator   Creator `json:"creator"`
+	Post      *Post   `json:"post,omitempty"`
+	Stream    *Stream `json:"stream,omitempty"`
+}
+
+type Creator struct {
+	ID         string `json:"id"`
+	Username   string `json:"username"`
+	AvatarURL  string `json:"avatar_url,omitempty"`
+	ProfileURL string `json:"profile_url"`
+}
+
+type Post struct {
+	ID        string `json:"id"`
+	Content   string `json:"content"`
+	URL       string `json:"url"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+type Stream struct {
+	URL         string `json:"url"`
+	ViewerCount int    `json:"viewer_count"`
+	StartedAt   int64  `json:"started_at"`
+}
+
+func creatorFor(user models.MonitoredUser) Creator {
+	return Creator{
+		ID:         user.UserID,
+		Username:   user.Username,
+		AvatarURL:  user.AvatarLocation,
+		ProfileURL: fmt.Sprintf("https://fansly.com/%s", user.Username),
+	}
+}
+
+// NewPostPayload describes a post published by a monitored creator.
+func NewPostPayload(guildID string, user models.MonitoredUser, post api.Post, timestamp int64) Payload {
+	return Payload{
+		Version:   PayloadVersion,
+		Event:     EventPostPublished,
+		Timestamp: timestamp,
+		GuildID:   guildID,
+		Creator:   creatorFor(user),
+		Post: &Post{
+			ID:        post.ID,
+			Content:   post.Content,
+			URL:       fmt.Sprintf("https://fans.ly/post/%s", post.ID),
+			CreatedAt: post.CreatedAt,
+		},
+	}
+}
+
+// NewStreamPayload describes a stream started by a monitored creator.
+func NewStreamPayload(guildID string, user models.MonitoredUser, stream *api.StreamResponse, timestamp int64) Payload {
+	return Payload{
+		Version:   PayloadVersion,
+		Event:     EventStreamStarted,
+		Timestamp: timestamp,
+		GuildID:   guildID,
+		Creator:   creatorFor(user),
+		Stream: &Stream{
+			URL:         fmt.Sprintf("https://fansly.com/live/%s", user.Username),
+			ViewerCount: stream.Response.Stream.ViewerCount,
+			StartedAt:   stream.Response.Stream.StartedAt,
+		},
+	}
+}
+
+// NewStreamEndedPayload describes a monitored creator's stream going offline.
+func NewStreamEndedPayload(guildID string, user models.MonitoredUser, startedAt int64, timestamp int64) Payload {
+	return Payload{
+		Version:   PayloadVersion,
+		Event:     EventStreamEnded,
+		Timestamp: timestamp,
+		GuildID:   guildID,
+		Creator:   creatorFor(user),
+		Stream: &Stream{
+			URL:       fmt.Sprintf("https://fansly.com/live/%s", user.Username),
+			StartedAt: startedAt,
+		},
+	}
+}